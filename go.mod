@@ -11,6 +11,7 @@ require (
 	github.com/muesli/go-app-paths v0.2.2
 	github.com/stretchr/testify v1.10.0
 	go.etcd.io/bbolt v1.4.2
+	golang.org/x/sync v0.15.0
 )
 
 require (
@@ -33,7 +34,6 @@ require (
 	github.com/rivo/uniseg v0.4.7 // indirect
 	github.com/sahilm/fuzzy v0.1.1 // indirect
 	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
-	golang.org/x/sync v0.15.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
 	golang.org/x/text v0.25.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
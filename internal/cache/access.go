@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// accessPrecision is the precision of the access time of the entries,
+// it limits the number of writes when an entry is read repeatedly.
+const accessPrecision = time.Minute
+
+// recordAccess records that the entry associated with key has just been read.
+// The access time is written by the next write or by [Cache.Flush].
+//
+// accessedAt is the access time currently stored in the entry.
+func (c *Cache[T]) recordAccess(key string, accessedAt int64) {
+	now := time.Now()
+	if now.Sub(time.Unix(accessedAt, 0)) < accessPrecision {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.pendingAccesses == nil {
+		c.pendingAccesses = make(map[string]int64)
+	}
+	c.pendingAccesses[key] = now.Unix()
+}
+
+// Flush writes the access times recorded by the reads since the last write.
+//
+// It is a no-op if the backend is read-only.
+func (c *Cache[T]) Flush() error {
+	if c.backend.IsReadOnly() {
+		return nil
+	}
+
+	pendingAccesses := c.takePendingAccesses()
+	if len(pendingAccesses) == 0 {
+		return nil
+	}
+
+	return c.backend.Update(
+		c.bucketName,
+		c.refreshAccessTimes(pendingAccesses),
+		slices.Collect(maps.Keys(pendingAccesses))...,
+	)
+}
+
+// takePendingAccesses returns the access times recorded since the last write
+// indexed by key.
+func (c *Cache[T]) takePendingAccesses() map[string]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pendingAccesses := maps.Clone(c.pendingAccesses)
+	clear(c.pendingAccesses)
+	return pendingAccesses
+}
+
+// refreshAccessTimes returns an [UpdateFunc] updating the access time of the entries
+// with their recorded access time, so that an entry written or deleted concurrently
+// is never overwritten with stale data.
+func (c *Cache[T]) refreshAccessTimes(pendingAccesses map[string]int64) UpdateFunc {
+	return func(key string, b []byte) ([]byte, bool, error) {
+		// The entry might have been deleted in the meantime.
+		if b == nil {
+			return nil, false, nil
+		}
+
+		var meta entryMetadata
+		if err := decodeEntry(b, &meta); err != nil {
+			return nil, false, err
+		}
+		meta.AccessedAt = max(meta.AccessedAt, pendingAccesses[key])
+
+		b, err := encodeEntry(meta, c.compress)
+		if err != nil {
+			return nil, false, err
+		}
+		return b, true, nil
+	}
+}
@@ -0,0 +1,40 @@
+package cache
+
+// Backend stores the encoded entries of the caches grouped by bucket.
+//
+// Implementations must be safe for concurrent use.
+type Backend interface {
+	// Get returns the encoded entry associated with key in bucket,
+	// or nil if there is none.
+	Get(bucket, key string) ([]byte, error)
+
+	// ForEach calls fn for each entry of bucket in key order and stops at the
+	// first error returned by fn. The value is only valid during the call to fn.
+	ForEach(bucket string, fn func(key string, value []byte) error) error
+
+	// Put stores all the entries in bucket, creating the bucket if it doesn't exist.
+	Put(bucket string, entries map[string][]byte) error
+
+	// Update atomically replaces the entries associated with keys in bucket, or all
+	// its entries if no keys are given, with the values returned by fn given their
+	// current value, nil if there is none. The value is only valid during the call to fn.
+	//
+	// Nothing is stored if fn returns an error.
+	// The bucket is created if it doesn't exist.
+	Update(bucket string, fn UpdateFunc, keys ...string) error
+
+	// Delete deletes the entries associated with keys in bucket.
+	// Keys without an entry are ignored.
+	Delete(bucket string, keys ...string) error
+
+	// DeleteBucket deletes bucket and all its entries.
+	// It is a no-op if the bucket doesn't exist.
+	DeleteBucket(bucket string) error
+
+	// IsReadOnly returns true if the entries cannot be modified, false otherwise.
+	IsReadOnly() bool
+}
+
+// UpdateFunc returns the new value of the entry associated with key given its current
+// value, nil if there is none, along with a boolean indicating whether to store it.
+type UpdateFunc func(key string, value []byte) ([]byte, bool, error)
@@ -0,0 +1,240 @@
+package cache_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/matthieugusmini/rift/internal/cache"
+)
+
+func TestBackends(t *testing.T) {
+	backends := map[string]func(t *testing.T) cache.Backend{
+		"bolt": func(t *testing.T) cache.Backend { return setupTempBoltBackend(t) },
+		"dir": func(t *testing.T) cache.Backend {
+			backend, err := cache.NewDirBackend(t.TempDir())
+			require.NoError(t, err)
+			return backend
+		},
+		"memory": func(*testing.T) cache.Backend { return cache.NewMemoryBackend() },
+	}
+
+	for name, newBackend := range backends {
+		t.Run(name, func(t *testing.T) {
+			t.Run("put and get", func(t *testing.T) {
+				backend := newBackend(t)
+
+				err := backend.Put("test-bucket", map[string][]byte{
+					"lec:1/2": []byte("Tralalero Tralala"),
+				})
+				require.NoError(t, err)
+
+				got, err := backend.Get("test-bucket", "lec:1/2")
+				require.NoError(t, err)
+				require.Equal(t, []byte("Tralalero Tralala"), got)
+			})
+
+			t.Run("put and iterate long keys", func(t *testing.T) {
+				backend := newBackend(t)
+				key := strings.Repeat("110733838935136200,", 20)
+
+				err := backend.Put("test-bucket", map[string][]byte{
+					key: []byte("Tralalero Tralala"),
+				})
+				require.NoError(t, err)
+
+				got, err := backend.Get("test-bucket", key)
+				require.NoError(t, err)
+				require.Equal(t, []byte("Tralalero Tralala"), got)
+				var keys []string
+				err = backend.ForEach("test-bucket", func(key string, _ []byte) error {
+					keys = append(keys, key)
+					return nil
+				})
+				require.NoError(t, err)
+				require.Equal(t, []string{key}, keys)
+			})
+
+			t.Run("put keys differing only by case", func(t *testing.T) {
+				backend := newBackend(t)
+
+				err := backend.Put("test-bucket", map[string][]byte{
+					"CAESBQ==": []byte("Brr Brr Patapim"),
+					"caesbq==": []byte("Lirili Larila"),
+				})
+				require.NoError(t, err)
+
+				got, err := backend.Get("test-bucket", "CAESBQ==")
+				require.NoError(t, err)
+				require.Equal(t, []byte("Brr Brr Patapim"), got)
+				got, err = backend.Get("test-bucket", "caesbq==")
+				require.NoError(t, err)
+				require.Equal(t, []byte("Lirili Larila"), got)
+			})
+
+			t.Run("get missing key", func(t *testing.T) {
+				backend := newBackend(t)
+
+				got, err := backend.Get("test-bucket", "Tralalero Tralala")
+
+				require.NoError(t, err)
+				require.Nil(t, got)
+			})
+
+			t.Run("iterate in key order", func(t *testing.T) {
+				backend := newBackend(t)
+				err := backend.Put("test-bucket", map[string][]byte{
+					"c": []byte("Tung Tung Tung Sahur"),
+					"a": []byte("Brr Brr Patapim"),
+					"b": []byte("Lirili Larila"),
+				})
+				require.NoError(t, err)
+
+				var keys []string
+				err = backend.ForEach("test-bucket", func(key string, _ []byte) error {
+					keys = append(keys, key)
+					return nil
+				})
+
+				require.NoError(t, err)
+				require.Equal(t, []string{"a", "b", "c"}, keys)
+			})
+
+			t.Run("delete keys and bucket", func(t *testing.T) {
+				backend := newBackend(t)
+				err := backend.Put("test-bucket", map[string][]byte{
+					"a": []byte("Brr Brr Patapim"),
+					"b": []byte("Lirili Larila"),
+				})
+				require.NoError(t, err)
+
+				require.NoError(t, backend.Delete("test-bucket", "a", "missing"))
+				got, err := backend.Get("test-bucket", "a")
+				require.NoError(t, err)
+				require.Nil(t, got)
+
+				require.NoError(t, backend.DeleteBucket("test-bucket"))
+				require.NoError(t, backend.DeleteBucket("missing-bucket"))
+				got, err = backend.Get("test-bucket", "b")
+				require.NoError(t, err)
+				require.Nil(t, got)
+			})
+
+			t.Run("update entries", func(t *testing.T) {
+				backend := newBackend(t)
+				err := backend.Put("test-bucket", map[string][]byte{
+					"a": []byte("Brr Brr Patapim"),
+					"b": []byte("Lirili Larila"),
+				})
+				require.NoError(t, err)
+
+				err = backend.Update(
+					"test-bucket",
+					func(key string, value []byte) ([]byte, bool, error) {
+						if value == nil {
+							return []byte("Tung Tung Tung Sahur"), true, nil
+						}
+						return []byte(string(value) + "!"), key == "a", nil
+					},
+					"a",
+					"b",
+					"c",
+				)
+				require.NoError(t, err)
+
+				var got []string
+				err = backend.ForEach("test-bucket", func(_ string, value []byte) error {
+					got = append(got, string(value))
+					return nil
+				})
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					[]string{"Brr Brr Patapim!", "Lirili Larila", "Tung Tung Tung Sahur"},
+					got,
+				)
+			})
+
+			t.Run("update every entry", func(t *testing.T) {
+				backend := newBackend(t)
+				err := backend.Put("test-bucket", map[string][]byte{
+					"a": []byte("Brr Brr Patapim"),
+					"b": []byte("Lirili Larila"),
+				})
+				require.NoError(t, err)
+
+				var keys []string
+				err = backend.Update(
+					"test-bucket",
+					func(key string, _ []byte) ([]byte, bool, error) {
+						keys = append(keys, key)
+						return nil, false, nil
+					},
+				)
+
+				require.NoError(t, err)
+				require.Equal(t, []string{"a", "b"}, keys)
+			})
+
+			t.Run("abort update on error", func(t *testing.T) {
+				backend := newBackend(t)
+				err := backend.Put("test-bucket", map[string][]byte{
+					"a": []byte("Brr Brr Patapim"),
+					"b": []byte("Lirili Larila"),
+				})
+				require.NoError(t, err)
+
+				err = backend.Update(
+					"test-bucket",
+					func(key string, _ []byte) ([]byte, bool, error) {
+						if key == "b" {
+							return nil, false, errors.New("Tralalero Tralala")
+						}
+						return []byte("Bombardiro Crocodilo"), true, nil
+					},
+					"a",
+					"b",
+				)
+
+				require.Error(t, err)
+				got, err := backend.Get("test-bucket", "a")
+				require.NoError(t, err)
+				require.Equal(t, []byte("Brr Brr Patapim"), got)
+			})
+
+			t.Run("cache round trip", func(t *testing.T) {
+				c := cache.New[string](newBackend(t), "test-bucket", 0)
+
+				require.NoError(t, c.Set("Tralalero Tralala", "Bombardiro Crocodilo"))
+
+				requireValue(t, c, "Tralalero Tralala", "Bombardiro Crocodilo")
+			})
+		})
+	}
+}
+
+func TestDirBackend(t *testing.T) {
+	t.Run("names files in lower case", func(t *testing.T) {
+		root := t.TempDir()
+		backend, err := cache.NewDirBackend(root)
+		require.NoError(t, err)
+
+		err = backend.Put("test-bucket", map[string][]byte{
+			"CAESBQ==": []byte("Brr Brr Patapim"),
+			"caesbq==": []byte("Lirili Larila"),
+		})
+		require.NoError(t, err)
+
+		files, err := os.ReadDir(filepath.Join(root, "test-bucket"))
+		require.NoError(t, err)
+		names := make(map[string]bool)
+		for _, file := range files {
+			names[strings.ToLower(file.Name())] = true
+		}
+		require.Len(t, names, 2)
+	})
+}
@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"errors"
+	"slices"
+
+	"go.etcd.io/bbolt"
+	bolterrors "go.etcd.io/bbolt/errors"
+)
+
+// BoltBackend is a [Backend] storing the entries in a [bbolt.DB] file,
+// with a bbolt bucket for each bucket.
+type BoltBackend struct {
+	db *bbolt.DB
+}
+
+// NewBoltBackend returns a new instance of a [BoltBackend] given a bbolt database.
+func NewBoltBackend(db *bbolt.DB) *BoltBackend {
+	return &BoltBackend{db: db}
+}
+
+// Get implements [Backend].
+func (b *BoltBackend) Get(bucket, key string) ([]byte, error) {
+	var value []byte
+
+	if err := b.db.View(func(tx *bbolt.Tx) error {
+		bkt := tx.Bucket([]byte(bucket))
+		if bkt == nil {
+			return nil
+		}
+
+		// The value is only valid for the life of the transaction.
+		value = slices.Clone(bkt.Get([]byte(key)))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// ForEach implements [Backend].
+func (b *BoltBackend) ForEach(bucket string, fn func(key string, value []byte) error) error {
+	return b.db.View(func(tx *bbolt.Tx) error {
+		bkt := tx.Bucket([]byte(bucket))
+		if bkt == nil {
+			return nil
+		}
+
+		return bkt.ForEach(func(k, v []byte) error {
+			return fn(string(k), v)
+		})
+	})
+}
+
+// Put implements [Backend].
+func (b *BoltBackend) Put(bucket string, entries map[string][]byte) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		if err != nil {
+			return err
+		}
+
+		for key, value := range entries {
+			if err := bkt.Put([]byte(key), value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Update implements [Backend].
+func (b *BoltBackend) Update(bucket string, fn UpdateFunc, keys ...string) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		if err != nil {
+			return err
+		}
+
+		// The bucket cannot be modified while iterating over it.
+		if len(keys) == 0 {
+			if err := bkt.ForEach(func(k, _ []byte) error {
+				keys = append(keys, string(k))
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+
+		for _, key := range keys {
+			value, ok, err := fn(key, bkt.Get([]byte(key)))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+
+			if err := bkt.Put([]byte(key), value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Delete implements [Backend].
+func (b *BoltBackend) Delete(bucket string, keys ...string) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		bkt := tx.Bucket([]byte(bucket))
+		if bkt == nil {
+			return nil
+		}
+
+		for _, key := range keys {
+			if err := bkt.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteBucket implements [Backend].
+func (b *BoltBackend) DeleteBucket(bucket string) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		err := tx.DeleteBucket([]byte(bucket))
+		if errors.Is(err, bolterrors.ErrBucketNotFound) {
+			return nil
+		}
+		return err
+	})
+}
+
+// IsReadOnly implements [Backend].
+func (b *BoltBackend) IsReadOnly() bool {
+	return b.db.IsReadOnly()
+}
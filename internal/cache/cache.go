@@ -3,108 +3,394 @@ package cache
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
 	"time"
-
-	"go.etcd.io/bbolt"
 )
 
-// Cache represents a file based cache which uses a [go.etcd.io.bbol.DB] under the hood
-// for fast reads and persistency across sessions.
+// ErrNotFound is returned when there is no entry associated with a key.
+var ErrNotFound = errors.New("entry not found")
+
+// Cache represents a cache storing its entries in a bucket of a [Backend],
+// by default a file based [BoltBackend] for fast reads and persistency across sessions.
 //
 // Entries stored in the cache can be invalidated using a TTL.
+//
+// Reads never write to the backend so they can run concurrently, the writes
+// they induce (e.g. access times) are deferred to the next write.
+// Expired entries are never deleted when read but in batches by a [Janitor].
 type Cache[T any] struct {
-	db         *bbolt.DB
+	backend    Backend
 	bucketName string
 	ttl        time.Duration
+	// Indicates whether the entries are compressed when stored.
+	compress bool
+
+	mu sync.Mutex
+	// Access times of the entries read since the last write, indexed by key.
+	pendingAccesses map[string]int64
 }
 
 type entry[T any] struct {
-	Value     T     `json:"value"`
-	ExpiresAt int64 `json:"expiresAt"`
+	Value      T     `json:"value"`
+	ExpiresAt  int64 `json:"expiresAt"`
+	UpdatedAt  int64 `json:"updatedAt,omitempty"`
+	AccessedAt int64 `json:"accessedAt,omitempty"`
+}
+
+// entryMetadata is an entry whose value is not decoded.
+type entryMetadata = entry[json.RawMessage]
+
+// Option represents a functional option to customize a [Cache].
+type Option func(*options)
+
+type options struct {
+	compress bool
 }
 
-// New returns a new instance of a [Cache] given a bbolt database, a time to live duration and a logger.
+// WithCompression compresses the entries stored by the cache with gzip.
+//
+// Entries are decompressed transparently when read, whether they have been
+// stored with compression or not.
+func WithCompression() Option {
+	return func(o *options) {
+		o.compress = true
+	}
+}
+
+// New returns a new instance of a [Cache] given a backend, the name of the bucket
+// storing the entries and a time to live duration.
 //
 // If ttl == 0 values stored in the cache are never invalidated.
-func New[T any](db *bbolt.DB, bucketName string, ttl time.Duration) *Cache[T] {
+func New[T any](backend Backend, bucketName string, ttl time.Duration, opts ...Option) *Cache[T] {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	return &Cache[T]{
-		db:         db,
+		backend:    backend,
 		bucketName: bucketName,
 		ttl:        ttl,
+		compress:   o.compress,
 	}
 }
 
 // Get returns the value associated to the key in the cache.
 //
 // Additionally a boolean is returned to indicated whether the value was found in the cache or not.
-// If the bracket template entry in the cache is subject to a TTL and has expired,
-// it is invalidated and false is returned.
+// If the entry in the cache is subject to a TTL and has expired, false is returned.
+// Expired entries are kept in the cache so they can still be retrieved using [Cache.GetStale].
 //
-// An error is returned if the entry is corrupted or if an error happens when trying to invalidate
-// the entry.
+// An error is returned if the entry is missing or corrupted.
 func (c *Cache[T]) Get(key string) (T, bool, error) {
-	var (
-		zero  T
-		entry entry[T]
-	)
-	if err := c.db.Update(func(tx *bbolt.Tx) error {
-		bucket := tx.Bucket([]byte(c.bucketName))
-		if bucket == nil {
-			return errors.New("bucket does not exist")
-		}
+	var zero T
 
-		b := bucket.Get([]byte(key))
-
-		return json.Unmarshal(b, &entry)
-	}); err != nil {
+	entry, err := c.get(key)
+	if err != nil {
 		return zero, false, err
 	}
 
-	hasExpired := entry.ExpiresAt > 0 && time.Now().Unix() > entry.ExpiresAt
-	if hasExpired {
-		err := c.delete(key)
-
-		return zero, false, err
+	if entry.hasExpired() {
+		return zero, false, nil
 	}
 
 	return entry.Value, true, nil
 }
 
-// Set stores a new entry for value in the cache associated with the given key.
+func (e entry[T]) hasExpired() bool {
+	return e.ExpiresAt > 0 && time.Now().Unix() > e.ExpiresAt
+}
+
+// GetStale returns the value associated to the key in the cache even if
+// the entry has expired, along with the time at which it was stored.
+//
+// It is meant to be used as a fallback when fresh data cannot be fetched
+// (e.g. network unavailable).
+//
+// Additionally a boolean is returned to indicated whether the value was found in the cache or not.
+// An error is returned if the entry is corrupted.
+func (c *Cache[T]) GetStale(key string) (T, time.Time, bool, error) {
+	var zero T
+
+	entry, err := c.get(key)
+	if err != nil {
+		return zero, time.Time{}, false, err
+	}
+
+	updatedAt := entry.UpdatedAt
+	// Entries written by older versions don't have the field.
+	if updatedAt == 0 {
+		updatedAt = time.Unix(entry.ExpiresAt, 0).Add(-c.ttl).Unix()
+	}
+
+	return entry.Value, time.Unix(updatedAt, 0), true, nil
+}
+
+func (c *Cache[T]) get(key string) (entry[T], error) {
+	var entry entry[T]
+
+	b, err := c.backend.Get(c.bucketName, key)
+	if err != nil {
+		return entry, err
+	}
+	if b == nil {
+		return entry, ErrNotFound
+	}
+
+	if err := decodeEntry(b, &entry); err != nil {
+		return entry, err
+	}
+
+	c.recordAccess(key, entry.AccessedAt)
+
+	return entry, nil
+}
+
+// Set stores a new entry for value in the cache associated with the given key
+// using the TTL of the cache.
 //
-// If the bucket used to store the rift.BracketTemplate doesn't exist in the underlying
-// daabase, it is automatically created.
+// If the bucket used to store the entries doesn't exist in the underlying
+// backend, it is automatically created.
 //
 // An error is returned if cannot create a new entry or a new bucket.
 func (c *Cache[T]) Set(key string, value T) error {
-	expiresAt := time.Now().Add(c.ttl).Unix()
+	return c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL is like [Cache.Set] but overrides the TTL of the cache for this entry.
+//
+// If ttl == 0 the entry is never invalidated.
+func (c *Cache[T]) SetWithTTL(key string, value T, ttl time.Duration) error {
+	now := time.Now()
 	entry := entry[T]{
-		Value:     value,
-		ExpiresAt: expiresAt,
+		Value:      value,
+		UpdatedAt:  now.Unix(),
+		AccessedAt: now.Unix(),
+	}
+	if ttl != 0 {
+		entry.ExpiresAt = now.Add(ttl).Unix()
 	}
 
-	b, err := json.Marshal(entry)
+	b, err := encodeEntry(entry, c.compress)
 	if err != nil {
 		return err
 	}
 
-	if err := c.db.Update(func(tx *bbolt.Tx) error {
-		bucket, err := tx.CreateBucketIfNotExists([]byte(c.bucketName))
+	// The access times recorded since the last write are written along with the entry.
+	pendingAccesses := c.takePendingAccesses()
+	delete(pendingAccesses, key)
+	refreshAccessTime := c.refreshAccessTimes(pendingAccesses)
+	keys := append(slices.Collect(maps.Keys(pendingAccesses)), key)
+
+	return c.backend.Update(c.bucketName, func(k string, old []byte) ([]byte, bool, error) {
+		if k == key {
+			return b, true, nil
+		}
+		return refreshAccessTime(k, old)
+	}, keys...)
+}
+
+// EntryInfo describes an entry stored in the cache without decoding its value.
+type EntryInfo struct {
+	Key string
+	// Size of the stored entry in bytes.
+	Size int
+	// Size of the entry in bytes once decompressed, equal to Size
+	// if the entry is not compressed.
+	DecompressedSize int
+	// ExpiresAt is the zero time if the entry never expires.
+	ExpiresAt time.Time
+	// UpdatedAt is the zero time if the entry was stored by an older version.
+	UpdatedAt time.Time
+	// AccessedAt is the last time the entry was read or written, with a precision
+	// of a minute. It is the zero time if the entry was stored by an older version.
+	AccessedAt time.Time
+}
+
+// HasExpired returns true if the entry is subject to a TTL and has expired, false otherwise.
+func (e EntryInfo) HasExpired() bool {
+	// Same second precision as the expiry time stored in the entry.
+	return !e.ExpiresAt.IsZero() && time.Now().Unix() > e.ExpiresAt.Unix()
+}
+
+// Stats represents the statistics of a cache bucket.
+type Stats struct {
+	Entries int
+	Expired int
+	// Size of all the stored entries in bytes.
+	Size int
+	// Size of all the entries in bytes once decompressed.
+	DecompressedSize int
+}
+
+// Saved returns the number of bytes saved by compressing the entries.
+func (s Stats) Saved() int {
+	return s.DecompressedSize - s.Size
+}
+
+// Entries returns the information of the entries stored in the cache, sorted by key.
+//
+// An error is returned if an entry is corrupted.
+func (c *Cache[T]) Entries() ([]EntryInfo, error) {
+	var infos []EntryInfo
+
+	if err := c.backend.ForEach(c.bucketName, func(key string, b []byte) error {
+		decompressed, err := decompress(b)
 		if err != nil {
-			return err
+			return fmt.Errorf("entry %q is corrupted: %w", key, err)
+		}
+
+		var meta entryMetadata
+		if err := json.Unmarshal(decompressed, &meta); err != nil {
+			return fmt.Errorf("entry %q is corrupted: %w", key, err)
 		}
 
-		return bucket.Put([]byte(key), b)
+		info := EntryInfo{
+			Key:              key,
+			Size:             len(b),
+			DecompressedSize: len(decompressed),
+		}
+		if meta.ExpiresAt > 0 {
+			info.ExpiresAt = time.Unix(meta.ExpiresAt, 0)
+		}
+		if meta.UpdatedAt > 0 {
+			info.UpdatedAt = time.Unix(meta.UpdatedAt, 0)
+		}
+		if meta.AccessedAt > 0 {
+			info.AccessedAt = time.Unix(meta.AccessedAt, 0)
+		}
+		infos = append(infos, info)
+
+		return nil
 	}); err != nil {
-		return err
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+// Stats returns the number of entries, expired or not, stored in the cache
+// and their total size.
+//
+// An error is returned if an entry is corrupted.
+func (c *Cache[T]) Stats() (Stats, error) {
+	infos, err := c.Entries()
+	if err != nil {
+		return Stats{}, err
+	}
+
+	var stats Stats
+	for _, info := range infos {
+		stats.Entries++
+		stats.Size += info.Size
+		stats.DecompressedSize += info.DecompressedSize
+		if info.HasExpired() {
+			stats.Expired++
+		}
 	}
 
-	return nil
+	return stats, nil
 }
 
-func (c *Cache[T]) delete(key string) error {
-	return c.db.Update(func(tx *bbolt.Tx) error {
-		bucket := tx.Bucket([]byte(c.bucketName))
-		return bucket.Delete([]byte(key))
-	})
+// DeletePrefix deletes all the entries whose key starts with prefix
+// and returns the number of deleted entries.
+//
+// All the entries are deleted if prefix is empty.
+func (c *Cache[T]) DeletePrefix(prefix string) (int, error) {
+	var keys []string
+	if err := c.backend.ForEach(c.bucketName, func(key string, _ []byte) error {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	if err := c.Delete(keys...); err != nil {
+		return 0, err
+	}
+
+	return len(keys), nil
+}
+
+// Delete deletes the entries associated with the given keys.
+// Keys without an entry are ignored.
+func (c *Cache[T]) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.backend.Delete(c.bucketName, keys...)
+}
+
+// Sweep deletes the entries which have expired for longer than retention
+// and returns the number of deleted entries.
+//
+// Expired entries are kept for a while as they can still be served using [Cache.GetStale]
+// when fresh data cannot be fetched.
+func (c *Cache[T]) Sweep(retention time.Duration) (int, error) {
+	infos, err := c.Entries()
+	if err != nil {
+		return 0, err
+	}
+
+	var keys []string
+	for _, info := range infos {
+		if !info.ExpiresAt.IsZero() && time.Since(info.ExpiresAt) > retention {
+			keys = append(keys, info.Key)
+		}
+	}
+
+	if err := c.Delete(keys...); err != nil {
+		return 0, err
+	}
+
+	return len(keys), nil
+}
+
+// Dump returns all the entries stored in the cache as JSON, decompressed, indexed by key
+// so they can be transferred to another cache using [Cache.Restore].
+func (c *Cache[T]) Dump() (map[string]json.RawMessage, error) {
+	entries := make(map[string]json.RawMessage)
+
+	if err := c.backend.ForEach(c.bucketName, func(key string, b []byte) error {
+		decompressed, err := decompress(b)
+		if err != nil {
+			return fmt.Errorf("entry %q is corrupted: %w", key, err)
+		}
+
+		// The value is only valid during the call.
+		entries[key] = slices.Clone(decompressed)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Restore stores the entries returned by [Cache.Dump], overwriting
+// the existing entries with the same keys. The expiry times of the entries are preserved.
+//
+// An error is returned and no entry is stored if one of them cannot be decoded.
+func (c *Cache[T]) Restore(entries map[string]json.RawMessage) error {
+	encoded := make(map[string][]byte, len(entries))
+	for key, b := range entries {
+		var entry entry[T]
+		if err := json.Unmarshal(b, &entry); err != nil {
+			return fmt.Errorf("entry %q is corrupted: %w", key, err)
+		}
+
+		b, err := encodeEntry(entry, c.compress)
+		if err != nil {
+			return err
+		}
+		encoded[key] = b
+	}
+
+	return c.backend.Put(c.bucketName, encoded)
 }
@@ -1,11 +1,14 @@
 package cache_test
 
 import (
+	"encoding/json"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/matthieugusmini/rift/internal/cache"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.etcd.io/bbolt"
 )
@@ -46,23 +49,186 @@ func TestCache(t *testing.T) {
 		require.NoError(t, err)
 		require.False(t, ok)
 	})
+
+	t.Run("set with ttl overrides cache ttl", func(t *testing.T) {
+		cache := setupExpiringCache[string](t)
+
+		err := cache.SetWithTTL("Capuccino Assassino", "Cappucina Ballerina", time.Hour)
+		require.NoError(t, err)
+
+		got, ok, err := cache.Get("Capuccino Assassino")
+
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "Cappucina Ballerina", got)
+	})
+
+	t.Run("set with zero ttl never expires", func(t *testing.T) {
+		cache := setupExpiringCache[string](t)
+
+		err := cache.SetWithTTL("Capuccino Assassino", "Cappucina Ballerina", 0)
+		require.NoError(t, err)
+
+		entries, err := cache.Entries()
+		require.NoError(t, err)
+		require.True(t, entries[0].ExpiresAt.IsZero())
+	})
+
+	t.Run("set and get stale expired", func(t *testing.T) {
+		cache := setupExpiringCache[string](t)
+		before := time.Now().Add(-time.Second)
+
+		err := cache.Set("Capuccino Assassino", "Cappucina Ballerina")
+		require.NoError(t, err)
+
+		got, updatedAt, ok, err := cache.GetStale("Capuccino Assassino")
+
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "Cappucina Ballerina", got)
+		require.False(t, updatedAt.Before(before))
+	})
+
+	t.Run("get stale missing key", func(t *testing.T) {
+		cache := setupTestCache[string](t)
+
+		_, _, ok, err := cache.GetStale("Tralalero Tralala")
+
+		require.False(t, ok)
+		require.Error(t, err)
+	})
+}
+
+func TestCache_AccessTime(t *testing.T) {
+	t.Run("writes access times of reads on flush", func(t *testing.T) {
+		c := setupTestCache[string](t)
+		accessedAt := time.Now().Add(-time.Hour)
+		restoreEntries(t, c, map[string]entryTimes{"Tralalero Tralala": {accessedAt: accessedAt}})
+
+		_, ok, err := c.Get("Tralalero Tralala")
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		entries, err := c.Entries()
+		require.NoError(t, err)
+		require.Equal(t, accessedAt.Unix(), entries[0].AccessedAt.Unix())
+
+		require.NoError(t, c.Flush())
+
+		entries, err = c.Entries()
+		require.NoError(t, err)
+		require.True(t, entries[0].AccessedAt.After(accessedAt))
+	})
+
+	t.Run("reads concurrently", func(t *testing.T) {
+		c := setupTestCache[string](t)
+		require.NoError(t, c.Set("Tralalero Tralala", "Bombardiro Crocodilo"))
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _, err := c.Get("Tralalero Tralala")
+				assert.NoError(t, err)
+				assert.NoError(t, c.Flush())
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestCache_Management(t *testing.T) {
+	t.Run("list entries and stats", func(t *testing.T) {
+		cache := setupExpiringCache[string](t)
+		require.NoError(t, cache.Set("Tung Tung Tung Sahur", "Lirili Larila"))
+		require.NoError(t, cache.Set("Brr Brr Patapim", "Trippi Troppi"))
+
+		entries, err := cache.Entries()
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Equal(t, "Brr Brr Patapim", entries[0].Key)
+		require.True(t, entries[0].HasExpired())
+		require.Positive(t, entries[0].Size)
+		require.False(t, entries[0].AccessedAt.IsZero())
+
+		stats, err := cache.Stats()
+		require.NoError(t, err)
+		require.Equal(t, 2, stats.Entries)
+		require.Equal(t, 2, stats.Expired)
+		require.Equal(t, entries[0].Size+entries[1].Size, stats.Size)
+	})
+
+	t.Run("list entries of missing bucket", func(t *testing.T) {
+		cache := setupTestCache[string](t)
+
+		entries, err := cache.Entries()
+
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("delete by prefix", func(t *testing.T) {
+		cache := setupTestCache[string](t)
+		for _, key := range []string{"lec:1", "lec:2", "lck:1", "lecx"} {
+			require.NoError(t, cache.Set(key, "Bombardiro Crocodilo"))
+		}
+
+		deleted, err := cache.DeletePrefix("lec:")
+		require.NoError(t, err)
+		require.Equal(t, 2, deleted)
+
+		entries, err := cache.Entries()
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+
+		deleted, err = cache.DeletePrefix("")
+		require.NoError(t, err)
+		require.Equal(t, 2, deleted)
+	})
+
+	t.Run("dump and restore", func(t *testing.T) {
+		src := setupTestCache[string](t)
+		require.NoError(t, src.Set("Tralalero Tralala", "Bombardiro Crocodilo"))
+		dst := setupTestCache[string](t)
+
+		entries, err := src.Dump()
+		require.NoError(t, err)
+		err = dst.Restore(entries)
+		require.NoError(t, err)
+
+		got, ok, err := dst.Get("Tralalero Tralala")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "Bombardiro Crocodilo", got)
+	})
+
+	t.Run("restore corrupted entry", func(t *testing.T) {
+		cache := setupTestCache[int](t)
+
+		err := cache.Restore(map[string]json.RawMessage{
+			"Tralalero Tralala": json.RawMessage(`{"value":"not an int"}`),
+		})
+
+		require.Error(t, err)
+	})
 }
 
 func setupTestCache[T any](t *testing.T) *cache.Cache[T] {
 	t.Helper()
 
-	db := setupTempDB(t)
-	return cache.New[T](db, "test-bucket", 0)
+	backend := setupTempBoltBackend(t)
+	return cache.New[T](backend, "test-bucket", 0)
 }
 
 func setupExpiringCache[T any](t *testing.T) *cache.Cache[T] {
 	t.Helper()
 
-	db := setupTempDB(t)
-	return cache.New[T](db, "test-bucket", -1*time.Second)
+	backend := setupTempBoltBackend(t)
+	return cache.New[T](backend, "test-bucket", -1*time.Second)
 }
 
-func setupTempDB(t *testing.T) *bbolt.DB {
+func setupTempBoltBackend(t *testing.T) *cache.BoltBackend {
 	t.Helper()
 
 	dbPath := filepath.Join(t.TempDir(), "test.db")
@@ -70,5 +236,5 @@ func setupTempDB(t *testing.T) *bbolt.DB {
 	require.NoError(t, err)
 	t.Cleanup(func() { db.Close() })
 
-	return db
+	return cache.NewBoltBackend(db)
 }
@@ -0,0 +1,276 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Prefix of the temporary files written before being renamed to the entry files.
+const dirTempFilePrefix = ".tmp-"
+
+// DirBackend is a [Backend] storing each entry in its own file,
+// with a directory for each bucket.
+//
+// The files are named after the hash of their key, so that keys which only differ
+// by case never collide on case-insensitive file systems, and the escaped key
+// is stored in their first line, followed by the value as is.
+type DirBackend struct {
+	root string
+	// Guards the files against concurrent access within the process,
+	// files are replaced atomically for the other processes.
+	//
+	// Updates are only atomic within the process, there is no lock
+	// shared with the other processes.
+	mu sync.RWMutex
+}
+
+// NewDirBackend returns a new instance of a [DirBackend] storing the entries
+// under root which is created if it doesn't exist.
+//
+// An error is returned if root cannot be created.
+func NewDirBackend(root string) (*DirBackend, error) {
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &DirBackend{root: root}, nil
+}
+
+// Get implements [Backend].
+func (d *DirBackend) Get(bucket, key string) ([]byte, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.readEntry(bucket, key)
+}
+
+// readEntry returns the value of the entry associated with key in bucket,
+// nil if there is none. It must be called with the lock held.
+func (d *DirBackend) readEntry(bucket, key string) ([]byte, error) {
+	b, err := os.ReadFile(d.entryPath(bucket, key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, value, _ := bytes.Cut(b, []byte("\n"))
+	return value, nil
+}
+
+// writeEntry writes the file of the entry associated with key in bucket whose
+// directory must exist. It must be called with the lock held.
+func (d *DirBackend) writeEntry(bucket, key string, value []byte) error {
+	b := slices.Concat([]byte(url.QueryEscape(key)+"\n"), value)
+	return writeFileAtomic(d.bucketPath(bucket), d.entryPath(bucket, key), b)
+}
+
+// ForEach implements [Backend].
+func (d *DirBackend) ForEach(bucket string, fn func(key string, value []byte) error) error {
+	keys, err := d.keys(bucket)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		b, err := d.Get(bucket, key)
+		if err != nil {
+			return err
+		}
+		// Deleted in the meantime.
+		if b == nil {
+			continue
+		}
+
+		if err := fn(key, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// keys returns the keys of the entries of bucket in order.
+func (d *DirBackend) keys(bucket string) ([]string, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.readKeys(bucket)
+}
+
+// readKeys is like keys but must be called with the lock held.
+func (d *DirBackend) readKeys(bucket string) ([]string, error) {
+	files, err := os.ReadDir(d.bucketPath(bucket))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), dirTempFilePrefix) {
+			continue
+		}
+
+		key, err := d.readKey(bucket, file.Name())
+		if err != nil {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys, nil
+}
+
+// readKey returns the key of the entry stored in the file with the given name.
+func (d *DirBackend) readKey(bucket, name string) (string, error) {
+	f, err := os.Open(filepath.Join(d.bucketPath(bucket), name))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	escapedKey, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return url.QueryUnescape(strings.TrimSuffix(escapedKey, "\n"))
+}
+
+// Put implements [Backend].
+func (d *DirBackend) Put(bucket string, entries map[string][]byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	bucketPath := d.bucketPath(bucket)
+	if err := os.MkdirAll(bucketPath, 0o700); err != nil {
+		return err
+	}
+
+	for key, value := range entries {
+		if err := d.writeEntry(bucket, key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Update implements [Backend].
+func (d *DirBackend) Update(bucket string, fn UpdateFunc, keys ...string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(keys) == 0 {
+		var err error
+		keys, err = d.readKeys(bucket)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Nothing is written until all the values are known.
+	updated := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		b, err := d.readEntry(bucket, key)
+		if err != nil {
+			return err
+		}
+
+		value, ok, err := fn(key, b)
+		if err != nil {
+			return err
+		}
+		if ok {
+			updated[key] = value
+		}
+	}
+
+	if err := os.MkdirAll(d.bucketPath(bucket), 0o700); err != nil {
+		return err
+	}
+
+	for key, value := range updated {
+		if err := d.writeEntry(bucket, key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeFileAtomic writes b to a temporary file in dir before renaming it to path
+// so that other processes never read a partially written file.
+func writeFileAtomic(dir, path string, b []byte) error {
+	f, err := os.CreateTemp(dir, dirTempFilePrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// Delete implements [Backend].
+func (d *DirBackend) Delete(bucket string, keys ...string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, key := range keys {
+		err := os.Remove(d.entryPath(bucket, key))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteBucket implements [Backend].
+func (d *DirBackend) DeleteBucket(bucket string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return os.RemoveAll(d.bucketPath(bucket))
+}
+
+// IsReadOnly implements [Backend].
+func (d *DirBackend) IsReadOnly() bool { return false }
+
+func (d *DirBackend) bucketPath(bucket string) string {
+	return filepath.Join(d.root, url.QueryEscape(bucket))
+}
+
+func (d *DirBackend) entryPath(bucket, key string) string {
+	return filepath.Join(d.bucketPath(bucket), entryFileName(key))
+}
+
+// entryFileName returns the name of the file storing the entry associated with key.
+//
+// It is lower case and short enough for any file system whatever the key.
+func entryFileName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+)
+
+// headerGzip is the first byte of the entries compressed with gzip.
+// Other entries are plain JSON objects, starting with '{'.
+const headerGzip byte = 0x01
+
+// encodeEntry encodes the entry as JSON, compressed with gzip if compress is true.
+func encodeEntry(entry any, compress bool) ([]byte, error) {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if !compress {
+		return b, nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(headerGzip)
+
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(b); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decodeEntry decodes an entry encoded by encodeEntry, compressed or not.
+func decodeEntry(b []byte, entry any) error {
+	b, err := decompress(b)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, entry)
+}
+
+// decompress returns the JSON of an entry encoded by encodeEntry.
+func decompress(b []byte) ([]byte, error) {
+	if !isCompressed(b) {
+		return b, nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(b[1:]))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	return io.ReadAll(zr)
+}
+
+func isCompressed(b []byte) bool {
+	return len(b) > 0 && b[0] == headerGzip
+}
@@ -0,0 +1,47 @@
+package cache_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/matthieugusmini/rift/internal/cache"
+)
+
+func TestCache_Compression(t *testing.T) {
+	value := strings.Repeat("Bombardiro Crocodilo ", 100)
+
+	t.Run("reads compressed and uncompressed entries", func(t *testing.T) {
+		backend := setupTempBoltBackend(t)
+		plain := cache.New[string](backend, "test-bucket", 0)
+		compressed := cache.New[string](backend, "test-bucket", 0, cache.WithCompression())
+		require.NoError(t, plain.Set("plain", value))
+		require.NoError(t, compressed.Set("compressed", value))
+
+		requireValue(t, compressed, "plain", value)
+		requireValue(t, plain, "compressed", value)
+	})
+
+	t.Run("reports saved space", func(t *testing.T) {
+		c := cache.New[string](setupTempBoltBackend(t), "test-bucket", 0, cache.WithCompression())
+		require.NoError(t, c.Set("Tralalero Tralala", value))
+
+		stats, err := c.Stats()
+
+		require.NoError(t, err)
+		require.Less(t, stats.Size, stats.DecompressedSize)
+		require.Positive(t, stats.Saved())
+	})
+
+	t.Run("dumps compressed entries as JSON", func(t *testing.T) {
+		c := cache.New[string](setupTempBoltBackend(t), "test-bucket", 0, cache.WithCompression())
+		require.NoError(t, c.Set("Tralalero Tralala", value))
+
+		entries, err := c.Dump()
+
+		require.NoError(t, err)
+		require.True(t, json.Valid(entries["Tralalero Tralala"]))
+	})
+}
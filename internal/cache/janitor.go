@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// Maintainable represents a cache whose entries can be removed by a [Janitor].
+type Maintainable interface {
+	Flush() error
+	Entries() ([]EntryInfo, error)
+	Delete(keys ...string) error
+	Sweep(retention time.Duration) (int, error)
+}
+
+// JanitorOption represents a functional option to customize a [Janitor].
+type JanitorOption func(*Janitor)
+
+// WithRetention sets how long expired entries are kept before being removed.
+func WithRetention(retention time.Duration) JanitorOption {
+	return func(j *Janitor) {
+		j.retention = retention
+	}
+}
+
+// WithMaxSize sets the maximum size in bytes of all the entries of the caches.
+// The least recently used entries are removed when it is exceeded.
+//
+// It bounds the size of the entries as reported by the caches, not the one of
+// the underlying storage, e.g. a bbolt file keeps the space freed to reuse it.
+//
+// If maxSize == 0 the size of the caches is unlimited.
+func WithMaxSize(maxSize int) JanitorOption {
+	return func(j *Janitor) {
+		j.maxSize = maxSize
+	}
+}
+
+// Janitor removes the outdated entries of a set of caches sharing the same database,
+// so that it does not grow forever.
+type Janitor struct {
+	caches    []Maintainable
+	retention time.Duration
+	maxSize   int
+	logger    *slog.Logger
+}
+
+// NewJanitor returns a new instance of a [Janitor] maintaining the given caches.
+//
+// By default expired entries are removed immediately and the size of the caches is unlimited.
+func NewJanitor(caches []Maintainable, logger *slog.Logger, opts ...JanitorOption) *Janitor {
+	j := &Janitor{
+		caches: caches,
+		logger: logger,
+	}
+
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	return j
+}
+
+// Clean removes the entries which have expired for longer than the retention period,
+// then the least recently used entries until the size of the caches is below the maximum size.
+//
+// It returns the number of removed entries.
+func (j *Janitor) Clean() (int, error) {
+	var removed int
+	for _, c := range j.caches {
+		// Access times must be up to date to evict the least recently used entries.
+		if err := c.Flush(); err != nil {
+			return removed, err
+		}
+
+		n, err := c.Sweep(j.retention)
+		if err != nil {
+			return removed, err
+		}
+		removed += n
+	}
+
+	if j.maxSize == 0 {
+		return removed, nil
+	}
+
+	n, err := j.evict()
+	return removed + n, err
+}
+
+// Run cleans the caches right away then every interval until ctx is canceled.
+//
+// If interval == 0 the caches are cleaned only once.
+func (j *Janitor) Run(ctx context.Context, interval time.Duration) {
+	j.cleanAndLog()
+
+	if interval == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.cleanAndLog()
+		}
+	}
+}
+
+func (j *Janitor) cleanAndLog() {
+	removed, err := j.Clean()
+	if err != nil {
+		j.logger.Warn("Failed to clean the cache", slog.Any("error", err))
+	}
+	if removed > 0 {
+		j.logger.Info("Removed outdated cache entries", slog.Int("count", removed))
+	}
+}
+
+// evict removes the least recently used entries of all the caches
+// until their total size is below the maximum size.
+func (j *Janitor) evict() (int, error) {
+	type candidate struct {
+		cache Maintainable
+		info  EntryInfo
+	}
+
+	var (
+		candidates []candidate
+		size       int
+	)
+	for _, c := range j.caches {
+		infos, err := c.Entries()
+		if err != nil {
+			return 0, err
+		}
+
+		for _, info := range infos {
+			candidates = append(candidates, candidate{cache: c, info: info})
+			size += info.Size
+		}
+	}
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return lastAccess(a.info).Compare(lastAccess(b.info))
+	})
+
+	var removed int
+	for _, candidate := range candidates {
+		if size <= j.maxSize {
+			break
+		}
+
+		if err := candidate.cache.Delete(candidate.info.Key); err != nil {
+			return removed, err
+		}
+		size -= candidate.info.Size
+		removed++
+	}
+
+	return removed, nil
+}
+
+// lastAccess returns the last time the entry was accessed, falling back
+// to its last update for entries stored by older versions.
+func lastAccess(info EntryInfo) time.Time {
+	if !info.AccessedAt.IsZero() {
+		return info.AccessedAt
+	}
+	return info.UpdatedAt
+}
@@ -0,0 +1,100 @@
+package cache_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/matthieugusmini/rift/internal/cache"
+)
+
+func TestJanitor_Clean(t *testing.T) {
+	t.Run("removes entries expired for longer than retention", func(t *testing.T) {
+		c := setupTestCache[string](t)
+		now := time.Now()
+		restoreEntries(t, c, map[string]entryTimes{
+			"Tung Tung Tung Sahur": {expiresAt: now.Add(-48 * time.Hour)},
+			"Brr Brr Patapim":      {expiresAt: now.Add(-time.Hour)},
+			"Lirili Larila":        {expiresAt: now.Add(time.Hour)},
+			"Trippi Troppi":        {},
+		})
+		janitor := cache.NewJanitor(
+			[]cache.Maintainable{c},
+			slog.New(slog.DiscardHandler),
+			cache.WithRetention(24*time.Hour),
+		)
+
+		removed, err := janitor.Clean()
+
+		require.NoError(t, err)
+		require.Equal(t, 1, removed)
+		requireKeys(t, c, "Brr Brr Patapim", "Lirili Larila", "Trippi Troppi")
+	})
+
+	t.Run("evicts least recently used entries above max size", func(t *testing.T) {
+		c := setupTestCache[string](t)
+		now := time.Now()
+		restoreEntries(t, c, map[string]entryTimes{
+			"a": {accessedAt: now.Add(-time.Hour)},
+			"b": {accessedAt: now.Add(-3 * time.Hour)},
+			"c": {accessedAt: now.Add(-2 * time.Hour)},
+		})
+		entries, err := c.Entries()
+		require.NoError(t, err)
+		janitor := cache.NewJanitor(
+			[]cache.Maintainable{c},
+			slog.New(slog.DiscardHandler),
+			// Room for a single entry.
+			cache.WithMaxSize(entries[0].Size),
+		)
+
+		removed, err := janitor.Clean()
+
+		require.NoError(t, err)
+		require.Equal(t, 2, removed)
+		requireKeys(t, c, "a")
+	})
+}
+
+type entryTimes struct {
+	expiresAt  time.Time
+	accessedAt time.Time
+}
+
+func restoreEntries(t *testing.T, c *cache.Cache[string], entries map[string]entryTimes) {
+	t.Helper()
+
+	raw := make(map[string]json.RawMessage)
+	for key, times := range entries {
+		var expiresAt, accessedAt int64
+		if !times.expiresAt.IsZero() {
+			expiresAt = times.expiresAt.Unix()
+		}
+		if !times.accessedAt.IsZero() {
+			accessedAt = times.accessedAt.Unix()
+		}
+		raw[key] = json.RawMessage(fmt.Sprintf(
+			`{"value":"Bombardiro Crocodilo","expiresAt":%d,"accessedAt":%d}`,
+			expiresAt,
+			accessedAt,
+		))
+	}
+	require.NoError(t, c.Restore(raw))
+}
+
+func requireKeys(t *testing.T, c *cache.Cache[string], want ...string) {
+	t.Helper()
+
+	entries, err := c.Entries()
+	require.NoError(t, err)
+
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Key)
+	}
+	require.Equal(t, want, got)
+}
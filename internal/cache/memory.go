@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"maps"
+	"slices"
+	"sync"
+)
+
+// MemoryBackend is a [Backend] keeping the entries in memory only,
+// they are lost when the process exits.
+type MemoryBackend struct {
+	mu      sync.RWMutex
+	buckets map[string]map[string][]byte
+}
+
+// NewMemoryBackend returns a new instance of an empty [MemoryBackend].
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		buckets: make(map[string]map[string][]byte),
+	}
+}
+
+// Get implements [Backend].
+func (m *MemoryBackend) Get(bucket, key string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return slices.Clone(m.buckets[bucket][key]), nil
+}
+
+// ForEach implements [Backend].
+func (m *MemoryBackend) ForEach(bucket string, fn func(key string, value []byte) error) error {
+	// Take a snapshot so that fn can modify the backend.
+	m.mu.RLock()
+	entries := maps.Clone(m.buckets[bucket])
+	m.mu.RUnlock()
+
+	for _, key := range slices.Sorted(maps.Keys(entries)) {
+		if err := fn(key, entries[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Put implements [Backend].
+func (m *MemoryBackend) Put(bucket string, entries map[string][]byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.buckets[bucket] == nil {
+		m.buckets[bucket] = make(map[string][]byte)
+	}
+	for key, value := range entries {
+		m.buckets[bucket][key] = slices.Clone(value)
+	}
+
+	return nil
+}
+
+// Update implements [Backend].
+func (m *MemoryBackend) Update(bucket string, fn UpdateFunc, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := m.buckets[bucket]
+	if len(keys) == 0 {
+		keys = slices.Sorted(maps.Keys(entries))
+	}
+
+	// Nothing is stored until all the values are known.
+	updated := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		value, ok, err := fn(key, entries[key])
+		if err != nil {
+			return err
+		}
+		if ok {
+			updated[key] = slices.Clone(value)
+		}
+	}
+
+	if entries == nil {
+		entries = make(map[string][]byte)
+		m.buckets[bucket] = entries
+	}
+	maps.Copy(entries, updated)
+
+	return nil
+}
+
+// Delete implements [Backend].
+func (m *MemoryBackend) Delete(bucket string, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.buckets[bucket], key)
+	}
+
+	return nil
+}
+
+// DeleteBucket implements [Backend].
+func (m *MemoryBackend) DeleteBucket(bucket string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.buckets, bucket)
+
+	return nil
+}
+
+// IsReadOnly implements [Backend].
+func (m *MemoryBackend) IsReadOnly() bool { return false }
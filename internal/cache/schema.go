@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// schemaBucketName is the name of the bucket storing the schema version of the other buckets.
+const schemaBucketName = "_schema"
+
+// Migration converts the encoded value of an entry written with an older schema version
+// to the current one.
+type Migration func(value json.RawMessage) (json.RawMessage, error)
+
+// Schema describes the format of the values stored in a bucket.
+type Schema struct {
+	// Version must be changed whenever the values stored in the bucket cannot be decoded
+	// the same way anymore (e.g. a field is renamed).
+	Version string
+	// Migrations convert the values written with older versions, indexed by version,
+	// to the current version. Buckets created before versioning was introduced
+	// have an empty version.
+	Migrations map[string]Migration
+}
+
+// SchemaResult represents what happened to a bucket when ensuring its schema.
+type SchemaResult int
+
+const (
+	// SchemaUpToDate means the entries of the bucket were already using the current version.
+	SchemaUpToDate SchemaResult = iota
+	// SchemaMigrated means the entries of the bucket were migrated to the current version.
+	SchemaMigrated
+	// SchemaWiped means the entries of the bucket were deleted because they
+	// could not be migrated to the current version.
+	SchemaWiped
+)
+
+// EnsureSchema makes sure all the entries of the bucket are stored using the current
+// version of the schema so that they are never decoded partially.
+//
+// Entries written with another version are migrated if a migration exists for that
+// version, otherwise the whole bucket is wiped.
+//
+// An error is returned if the schema version cannot be read or written.
+// If the backend is read-only, an error is returned if the bucket is not empty
+// and uses another version.
+func EnsureSchema(backend Backend, bucketName string, schema Schema) (SchemaResult, error) {
+	result, err := ensureSchema(backend, bucketName, schema)
+	if err != nil {
+		return result, fmt.Errorf(
+			"could not ensure the schema of the %s bucket: %w",
+			bucketName,
+			err,
+		)
+	}
+	return result, nil
+}
+
+func ensureSchema(backend Backend, bucketName string, schema Schema) (SchemaResult, error) {
+	b, err := backend.Get(schemaBucketName, bucketName)
+	if err != nil {
+		return SchemaUpToDate, err
+	}
+
+	version := string(b)
+	if version == schema.Version {
+		return SchemaUpToDate, nil
+	}
+
+	empty, err := isEmpty(backend, bucketName)
+	if err != nil {
+		return SchemaUpToDate, err
+	}
+
+	if backend.IsReadOnly() {
+		if empty {
+			return SchemaUpToDate, nil
+		}
+		return SchemaUpToDate, fmt.Errorf(
+			"outdated schema version %q in a read-only cache",
+			version,
+		)
+	}
+
+	result := SchemaUpToDate
+	if !empty {
+		result, err = migrateBucket(backend, bucketName, schema.Migrations[version])
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, backend.Put(
+		schemaBucketName,
+		map[string][]byte{bucketName: []byte(schema.Version)},
+	)
+}
+
+func migrateBucket(backend Backend, bucketName string, migrate Migration) (SchemaResult, error) {
+	if migrate == nil {
+		return SchemaWiped, backend.DeleteBucket(bucketName)
+	}
+
+	// All the entries are migrated at once so that no entry is ever written
+	// in between using another version.
+	if err := backend.Update(bucketName, func(_ string, b []byte) ([]byte, bool, error) {
+		var meta entryMetadata
+		if err := decodeEntry(b, &meta); err != nil {
+			return nil, false, err
+		}
+
+		value, err := migrate(meta.Value)
+		if err != nil {
+			return nil, false, err
+		}
+		meta.Value = value
+
+		// Keep the entries compressed or not.
+		b, err = encodeEntry(meta, isCompressed(b))
+		if err != nil {
+			return nil, false, err
+		}
+
+		return b, true, nil
+	}); err != nil {
+		// Better to lose the cached data than serving corrupted data.
+		return SchemaWiped, backend.DeleteBucket(bucketName)
+	}
+
+	return SchemaMigrated, nil
+}
+
+// SchemaVersion returns the version of the schema of the entries stored in the cache,
+// empty if the schema of the bucket has never been ensured.
+func (c *Cache[T]) SchemaVersion() (string, error) {
+	b, err := c.backend.Get(schemaBucketName, c.bucketName)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+var errStop = errors.New("stop")
+
+func isEmpty(backend Backend, bucketName string) (bool, error) {
+	err := backend.ForEach(bucketName, func(string, []byte) error {
+		return errStop
+	})
+	if errors.Is(err, errStop) {
+		return false, nil
+	}
+	return err == nil, err
+}
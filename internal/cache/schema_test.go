@@ -0,0 +1,91 @@
+package cache_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/matthieugusmini/rift/internal/cache"
+)
+
+func TestEnsureSchema(t *testing.T) {
+	t.Run("keeps entries with the current version", func(t *testing.T) {
+		backend := setupTempBoltBackend(t)
+		schema := cache.Schema{Version: "1"}
+		_, err := cache.EnsureSchema(backend, "test-bucket", schema)
+		require.NoError(t, err)
+		c := cache.New[string](backend, "test-bucket", 0)
+		require.NoError(t, c.Set("Tralalero Tralala", "Bombardiro Crocodilo"))
+
+		result, err := cache.EnsureSchema(backend, "test-bucket", schema)
+
+		require.NoError(t, err)
+		require.Equal(t, cache.SchemaUpToDate, result)
+		requireValue(t, c, "Tralalero Tralala", "Bombardiro Crocodilo")
+	})
+
+	t.Run("wipes unversioned entries without migration", func(t *testing.T) {
+		backend := setupTempBoltBackend(t)
+		c := cache.New[string](backend, "test-bucket", 0)
+		require.NoError(t, c.Set("Tralalero Tralala", "Bombardiro Crocodilo"))
+
+		result, err := cache.EnsureSchema(backend, "test-bucket", cache.Schema{Version: "1"})
+
+		require.NoError(t, err)
+		require.Equal(t, cache.SchemaWiped, result)
+		entries, err := c.Entries()
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("migrates entries of older version", func(t *testing.T) {
+		backend := setupTempBoltBackend(t)
+		_, err := cache.EnsureSchema(backend, "test-bucket", cache.Schema{Version: "1"})
+		require.NoError(t, err)
+		c := cache.New[string](backend, "test-bucket", 0)
+		require.NoError(t, c.Set("Tralalero Tralala", "Bombardiro Crocodilo"))
+
+		result, err := cache.EnsureSchema(backend, "test-bucket", cache.Schema{
+			Version: "2",
+			Migrations: map[string]cache.Migration{
+				"1": func(json.RawMessage) (json.RawMessage, error) {
+					return json.RawMessage(`"Cappucina Ballerina"`), nil
+				},
+			},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, cache.SchemaMigrated, result)
+		requireValue(t, c, "Tralalero Tralala", "Cappucina Ballerina")
+	})
+
+	t.Run("wipes entries if migration fails", func(t *testing.T) {
+		backend := setupTempBoltBackend(t)
+		c := cache.New[string](backend, "test-bucket", 0)
+		require.NoError(t, c.Set("Tralalero Tralala", "Bombardiro Crocodilo"))
+
+		result, err := cache.EnsureSchema(backend, "test-bucket", cache.Schema{
+			Version: "1",
+			Migrations: map[string]cache.Migration{
+				"": func(json.RawMessage) (json.RawMessage, error) {
+					return nil, errors.New("Tung Tung Tung Sahur")
+				},
+			},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, cache.SchemaWiped, result)
+		requireKeys(t, c)
+	})
+}
+
+func requireValue(t *testing.T, c *cache.Cache[string], key, want string) {
+	t.Helper()
+
+	got, ok, err := c.Get(key)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, want, got)
+}
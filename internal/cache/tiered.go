@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Tiered is a two-tier cache which keeps the decoded values of the most recently
+// used entries of a [Cache] in memory to avoid reading and decoding them from
+// the database each time.
+//
+// Values are shared between the callers and must not be modified.
+type Tiered[T any] struct {
+	persistent *Cache[T]
+	capacity   int
+
+	mu sync.Mutex
+	// Most recently used entries first.
+	lru      *list.List
+	elements map[string]*list.Element
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type memoryEntry[T any] struct {
+	key   string
+	value T
+	// Zero if the entry never expires.
+	expiresAt time.Time
+}
+
+func (e memoryEntry[T]) hasExpired() bool {
+	return !e.expiresAt.IsZero() && time.Now().After(e.expiresAt)
+}
+
+// Counters represents the number of reads served from memory or not.
+type Counters struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// NewTiered returns a new instance of a [Tiered] cache keeping at most
+// capacity entries of persistent in memory.
+func NewTiered[T any](persistent *Cache[T], capacity int) *Tiered[T] {
+	return &Tiered[T]{
+		persistent: persistent,
+		capacity:   capacity,
+		lru:        list.New(),
+		elements:   make(map[string]*list.Element),
+	}
+}
+
+// Get returns the value associated to the key from memory if present,
+// otherwise from the underlying [Cache] which is then kept in memory.
+//
+// It behaves like [Cache.Get] otherwise.
+func (t *Tiered[T]) Get(key string) (T, bool, error) {
+	if value, ok := t.getFromMemory(key); ok {
+		t.hits.Add(1)
+		// Keep track of the access so that the entry is not evicted from the database.
+		t.persistent.recordAccess(key, 0)
+		return value, true, nil
+	}
+	t.misses.Add(1)
+
+	var zero T
+
+	entry, err := t.persistent.get(key)
+	if err != nil {
+		return zero, false, err
+	}
+
+	if entry.hasExpired() {
+		return zero, false, nil
+	}
+
+	var expiresAt time.Time
+	if entry.ExpiresAt > 0 {
+		expiresAt = time.Unix(entry.ExpiresAt, 0)
+	}
+	t.setInMemory(key, entry.Value, expiresAt)
+
+	return entry.Value, true, nil
+}
+
+// GetStale returns the value associated to the key from the underlying [Cache]
+// even if it has expired. See [Cache.GetStale].
+func (t *Tiered[T]) GetStale(key string) (T, time.Time, bool, error) {
+	return t.persistent.GetStale(key)
+}
+
+// Set stores the value in the underlying [Cache] and in memory.
+// See [Cache.Set].
+func (t *Tiered[T]) Set(key string, value T) error {
+	return t.SetWithTTL(key, value, t.persistent.ttl)
+}
+
+// SetWithTTL stores the value in the underlying [Cache] and in memory.
+// See [Cache.SetWithTTL].
+func (t *Tiered[T]) SetWithTTL(key string, value T, ttl time.Duration) error {
+	if err := t.persistent.SetWithTTL(key, value, ttl); err != nil {
+		t.deleteFromMemory(key)
+		return err
+	}
+
+	var expiresAt time.Time
+	if ttl != 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+	t.setInMemory(key, value, expiresAt)
+
+	return nil
+}
+
+// Counters returns the number of reads served from memory (hits)
+// and from the underlying [Cache] (misses) since the creation of the cache.
+func (t *Tiered[T]) Counters() Counters {
+	return Counters{
+		Hits:   t.hits.Load(),
+		Misses: t.misses.Load(),
+	}
+}
+
+func (t *Tiered[T]) getFromMemory(key string) (T, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var zero T
+
+	elem, ok := t.elements[key]
+	if !ok {
+		return zero, false
+	}
+
+	entry := elem.Value.(memoryEntry[T])
+	if entry.hasExpired() {
+		t.lru.Remove(elem)
+		delete(t.elements, key)
+		return zero, false
+	}
+
+	t.lru.MoveToFront(elem)
+	return entry.value, true
+}
+
+func (t *Tiered[T]) setInMemory(key string, value T, expiresAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry := memoryEntry[T]{key: key, value: value, expiresAt: expiresAt}
+	if elem, ok := t.elements[key]; ok {
+		elem.Value = entry
+		t.lru.MoveToFront(elem)
+		return
+	}
+
+	t.elements[key] = t.lru.PushFront(entry)
+
+	for t.lru.Len() > t.capacity {
+		oldest := t.lru.Back()
+		t.lru.Remove(oldest)
+		delete(t.elements, oldest.Value.(memoryEntry[T]).key)
+	}
+}
+
+func (t *Tiered[T]) deleteFromMemory(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if elem, ok := t.elements[key]; ok {
+		t.lru.Remove(elem)
+		delete(t.elements, key)
+	}
+}
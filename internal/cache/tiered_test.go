@@ -0,0 +1,67 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/matthieugusmini/rift/internal/cache"
+)
+
+func TestTiered(t *testing.T) {
+	t.Run("serves from memory after the first read", func(t *testing.T) {
+		persistent := setupTestCache[string](t)
+		require.NoError(t, persistent.Set("Tralalero Tralala", "Bombardiro Crocodilo"))
+		tiered := cache.NewTiered(persistent, 2)
+
+		for range 3 {
+			got, ok, err := tiered.Get("Tralalero Tralala")
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, "Bombardiro Crocodilo", got)
+		}
+
+		require.Equal(t, cache.Counters{Hits: 2, Misses: 1}, tiered.Counters())
+	})
+
+	t.Run("set writes through", func(t *testing.T) {
+		persistent := setupTestCache[string](t)
+		tiered := cache.NewTiered(persistent, 2)
+
+		err := tiered.Set("Tralalero Tralala", "Bombardiro Crocodilo")
+		require.NoError(t, err)
+
+		got, ok, err := persistent.Get("Tralalero Tralala")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "Bombardiro Crocodilo", got)
+		_, _, err = tiered.Get("Tralalero Tralala")
+		require.NoError(t, err)
+		require.Equal(t, cache.Counters{Hits: 1}, tiered.Counters())
+	})
+
+	t.Run("evicts least recently used entries above capacity", func(t *testing.T) {
+		persistent := setupTestCache[string](t)
+		tiered := cache.NewTiered(persistent, 1)
+		require.NoError(t, tiered.Set("Tung Tung Tung Sahur", "Lirili Larila"))
+		require.NoError(t, tiered.Set("Brr Brr Patapim", "Trippi Troppi"))
+
+		_, ok, err := tiered.Get("Tung Tung Tung Sahur")
+
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, cache.Counters{Misses: 1}, tiered.Counters())
+	})
+
+	t.Run("does not serve expired entries", func(t *testing.T) {
+		persistent := setupTestCache[string](t)
+		tiered := cache.NewTiered(persistent, 2)
+		require.NoError(t, tiered.SetWithTTL("Capuccino Assassino", "Cappucina Ballerina", -time.Second))
+
+		_, ok, err := tiered.Get("Capuccino Assassino")
+
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
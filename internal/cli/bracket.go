@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/matthieugusmini/go-lolesports"
+
+	"github.com/matthieugusmini/rift/internal/rift"
+	"github.com/matthieugusmini/rift/internal/ui"
+)
+
+func (a *App) runBracket(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("bracket", "--stage <id> [flags]")
+	var (
+		stageID = fs.String("stage", "", "ID of the bracket stage")
+		league  = fs.String(
+			"league",
+			"",
+			"Name of the league the stage belongs to, speeds up the stage lookup",
+		)
+		width   = fs.Int("width", 0, "Width used to center the bracket (default to its own width)")
+		noColor = fs.Bool("no-color", false, "Strip the ANSI escape sequences from the output")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *stageID == "" {
+		fs.Usage()
+		return errors.New("the --stage flag is required")
+	}
+
+	stage, err := a.findStage(ctx, *stageID, *league)
+	if err != nil {
+		return err
+	}
+
+	if !rift.IsBracketStage(stage) {
+		return fmt.Errorf("stage %q is not a bracket stage", stage.Name)
+	}
+
+	// Bracket stages always have a single section.
+	matches := stage.Sections[0].Matches
+
+	tmpl, err := a.bracketTemplateLoader.Load(ctx, stage.ID, matches)
+	if err != nil {
+		return fmt.Errorf("could not load the bracket template: %w", err)
+	}
+
+	bracket := ui.RenderBracket(tmpl, matches, *width)
+	if *noColor {
+		bracket = ansi.Strip(bracket)
+	}
+
+	_, err = fmt.Fprintln(a.stdout, bracket)
+	return err
+}
+
+// findStage looks up the stage identified by stageID in the standings of
+// all the tournaments of the current season, or only those of leagueName if not empty.
+func (a *App) findStage(
+	ctx context.Context,
+	stageID, leagueName string,
+) (lolesports.Stage, error) {
+	splits, err := a.lolesportsLoader.LoadCurrentSeasonSplits(ctx)
+	if err != nil {
+		return lolesports.Stage{}, fmt.Errorf("could not load the current season splits: %w", err)
+	}
+
+	var tournamentIDs []string
+	for _, split := range splits {
+		if leagueName == "" {
+			for _, tournament := range split.Tournaments {
+				tournamentIDs = append(tournamentIDs, tournament.ID)
+			}
+			continue
+		}
+
+		league, err := findLeague(rift.ListLeaguesFromTournaments(split.Tournaments), leagueName)
+		if err != nil {
+			continue
+		}
+		tournamentIDs = append(
+			tournamentIDs,
+			rift.ListTournamentIDsForLeague(split.Tournaments, league.ID)...,
+		)
+	}
+	if len(tournamentIDs) == 0 {
+		return lolesports.Stage{}, errors.New("no tournament found in the current season")
+	}
+
+	standings, err := a.lolesportsLoader.LoadStandingsByTournamentIDs(ctx, tournamentIDs)
+	if err != nil {
+		return lolesports.Stage{}, fmt.Errorf("could not load the standings: %w", err)
+	}
+
+	for _, stage := range rift.ListStagesFromStandings(standings) {
+		if stage.ID == stageID {
+			return stage, nil
+		}
+	}
+
+	return lolesports.Stage{}, fmt.Errorf("stage %q not found in the current season", stageID)
+}
@@ -0,0 +1,60 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matthieugusmini/rift/internal/cli"
+)
+
+func TestApp_Bracket(t *testing.T) {
+	t.Run("prints the bracket of the stage", func(t *testing.T) {
+		stdout := runCommand(
+			t,
+			newStubLoLEsportsLoader(),
+			"bracket", "--stage", "playoffs", "--no-color",
+		)
+
+		got := stdout.String()
+		assert.Equal(t, got, ansi.Strip(got))
+		assert.Contains(t, got, "Final")
+		assert.Contains(t, got, "G2 3")
+		assert.Contains(t, got, "FNC 1")
+	})
+
+	t.Run("prints the bracket of the stage of a league", func(t *testing.T) {
+		stdout := runCommand(
+			t,
+			newStubLoLEsportsLoader(),
+			"bracket", "--stage", "playoffs", "--league", "LEC",
+		)
+
+		assert.Contains(t, stdout.String(), "Final")
+	})
+
+	tt := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing stage", args: []string{"bracket"}},
+		{name: "unknown stage", args: []string{"bracket", "--stage", "groups"}},
+		{name: "not a bracket stage", args: []string{"bracket", "--stage", "regular"}},
+		{name: "unknown league", args: []string{"bracket", "--stage", "playoffs", "--league", "LFL"}},
+	}
+	for _, tc := range tt {
+		t.Run("returns error if "+tc.name, func(t *testing.T) {
+			app := cli.New(
+				newStubLoLEsportsLoader(),
+				newStubBracketTemplateLoader(),
+				cli.WithOutput(&bytes.Buffer{}, &bytes.Buffer{}),
+			)
+
+			err := app.Run(t.Context(), tc.args)
+
+			assert.Error(t, err)
+		})
+	}
+}
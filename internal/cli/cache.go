@@ -0,0 +1,350 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/matthieugusmini/rift/internal/cache"
+)
+
+// Cache manages the entries of a cache bucket.
+type Cache interface {
+	Entries() ([]cache.EntryInfo, error)
+	Stats() (cache.Stats, error)
+	DeletePrefix(prefix string) (int, error)
+	Dump() (map[string]json.RawMessage, error)
+	Restore(entries map[string]json.RawMessage) error
+	SchemaVersion() (string, error)
+}
+
+// Version 2 added the schema version of each bucket.
+const cacheExportVersion = 2
+
+// cacheExport is the format of the file written by the cache export command.
+type cacheExport struct {
+	Version int                                   `json:"version"`
+	Buckets map[string]map[string]json.RawMessage `json:"buckets"`
+	// Schema version of the entries of each bucket, so that entries exported
+	// by another version of Rift are never imported into a migrated bucket.
+	Schemas map[string]string `json:"schemas"`
+}
+
+var cacheSubcommands = map[string]func(app *App, args []string) error{
+	"stats":  (*App).runCacheStats,
+	"ls":     (*App).runCacheList,
+	"purge":  (*App).runCachePurge,
+	"export": (*App).runCacheExport,
+	"import": (*App).runCacheImport,
+}
+
+func (a *App) runCache(_ context.Context, args []string) error {
+	usage := func() {
+		fmt.Fprintln(a.stderr, "Usage: rift cache <stats|ls|purge|export|import> [flags]")
+	}
+
+	if len(args) == 0 {
+		usage()
+		return errors.New("no cache command provided")
+	}
+	if args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage()
+		return nil
+	}
+
+	run, ok := cacheSubcommands[args[0]]
+	if !ok {
+		usage()
+		return fmt.Errorf("unknown cache command %q", args[0])
+	}
+
+	return run(a, args[1:])
+}
+
+func (a *App) runCacheStats(args []string) error {
+	fs := a.newFlagSet("cache stats", "[flags]")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BUCKET\tENTRIES\tEXPIRED\tSIZE\tSAVED")
+
+	var total cache.Stats
+	for _, name := range a.cacheNames() {
+		stats, err := a.caches[name].Stats()
+		if err != nil {
+			return fmt.Errorf("could not read the %s bucket: %w", name, err)
+		}
+		total.Entries += stats.Entries
+		total.Expired += stats.Expired
+		total.Size += stats.Size
+		total.DecompressedSize += stats.DecompressedSize
+
+		writeCacheStats(tw, name, stats)
+	}
+	writeCacheStats(tw, "total", total)
+
+	return tw.Flush()
+}
+
+func writeCacheStats(w io.Writer, name string, stats cache.Stats) {
+	fmt.Fprintf(
+		w,
+		"%s\t%d\t%d\t%s\t%s\n",
+		name,
+		stats.Entries,
+		stats.Expired,
+		formatSize(stats.Size),
+		formatSaved(stats),
+	)
+}
+
+func (a *App) runCacheList(args []string) error {
+	fs := a.newFlagSet("cache ls", "[flags]")
+	var (
+		bucket = fs.String("bucket", "", "Only list the entries of this bucket")
+		prefix = fs.String("prefix", "", "Only list the entries whose key starts with this prefix")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	names, err := a.selectCaches(*bucket)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BUCKET\tKEY\tSIZE\tUPDATED\tEXPIRES")
+	for _, name := range names {
+		entries, err := a.caches[name].Entries()
+		if err != nil {
+			return fmt.Errorf("could not read the %s bucket: %w", name, err)
+		}
+
+		for _, entry := range entries {
+			if !strings.HasPrefix(entry.Key, *prefix) {
+				continue
+			}
+			fmt.Fprintf(
+				tw,
+				"%s\t%s\t%s\t%s\t%s\n",
+				name,
+				entry.Key,
+				formatSize(entry.Size),
+				formatCacheTime(entry.UpdatedAt, "unknown"),
+				formatExpiry(entry),
+			)
+		}
+	}
+
+	return tw.Flush()
+}
+
+func (a *App) runCachePurge(args []string) error {
+	fs := a.newFlagSet("cache purge", "[flags]")
+	var (
+		bucket = fs.String(
+			"bucket",
+			"",
+			"Only delete the entries of this bucket (required for the buckets of user state)",
+		)
+		prefix = fs.String("prefix", "", "Only delete the entries whose key starts with this prefix")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	names, err := a.selectCaches(*bucket)
+	if err != nil {
+		return err
+	}
+	// Purging is meant to fetch the data again, the user state cannot be fetched.
+	if *bucket == "" {
+		names = slices.DeleteFunc(names, func(name string) bool {
+			return slices.Contains(a.preservedBuckets, name)
+		})
+	}
+
+	var deleted int
+	for _, name := range names {
+		n, err := a.caches[name].DeletePrefix(*prefix)
+		if err != nil {
+			return fmt.Errorf("could not purge the %s bucket: %w", name, err)
+		}
+		deleted += n
+	}
+
+	_, err = fmt.Fprintf(a.stdout, "Deleted %d entries\n", deleted)
+	return err
+}
+
+func (a *App) runCacheExport(args []string) error {
+	fs := a.newFlagSet("cache export", "[flags]")
+	output := fs.String("output", "", "Path of the file to write (default to stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	export := cacheExport{
+		Version: cacheExportVersion,
+		Buckets: make(map[string]map[string]json.RawMessage),
+		Schemas: make(map[string]string),
+	}
+	for _, name := range a.cacheNames() {
+		entries, err := a.caches[name].Dump()
+		if err != nil {
+			return fmt.Errorf("could not read the %s bucket: %w", name, err)
+		}
+		export.Buckets[name] = entries
+
+		version, err := a.caches[name].SchemaVersion()
+		if err != nil {
+			return fmt.Errorf("could not read the schema of the %s bucket: %w", name, err)
+		}
+		export.Schemas[name] = version
+	}
+
+	w := a.stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("could not create the export file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	return enc.Encode(export)
+}
+
+func (a *App) runCacheImport(args []string) error {
+	fs := a.newFlagSet("cache import", "<file|->")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("the file to import is required")
+	}
+
+	var r io.Reader = os.Stdin
+	if path := fs.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("could not open the export file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var export cacheExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return fmt.Errorf("could not decode the export file: %w", err)
+	}
+	if export.Version != cacheExportVersion {
+		return fmt.Errorf("unsupported export file version %d", export.Version)
+	}
+
+	var imported int
+	for name, entries := range export.Buckets {
+		c, ok := a.caches[name]
+		if !ok {
+			fmt.Fprintf(a.stderr, "Warning: skipping unknown bucket %q\n", name)
+			continue
+		}
+
+		version, err := c.SchemaVersion()
+		if err != nil {
+			return fmt.Errorf("could not read the schema of the %s bucket: %w", name, err)
+		}
+		if exported := export.Schemas[name]; exported != version {
+			fmt.Fprintf(
+				a.stderr,
+				"Warning: skipping bucket %q exported with schema version %q instead of %q\n",
+				name,
+				exported,
+				version,
+			)
+			continue
+		}
+
+		if err := c.Restore(entries); err != nil {
+			return fmt.Errorf("could not import the %s bucket: %w", name, err)
+		}
+		imported += len(entries)
+	}
+
+	_, err := fmt.Fprintf(a.stdout, "Imported %d entries\n", imported)
+	return err
+}
+
+// cacheNames returns the names of the cache buckets in alphabetical order.
+func (a *App) cacheNames() []string {
+	names := make([]string, 0, len(a.caches))
+	for name := range a.caches {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// selectCaches returns the name of the bucket if not empty or all of them otherwise.
+func (a *App) selectCaches(bucket string) ([]string, error) {
+	if bucket == "" {
+		return a.cacheNames(), nil
+	}
+
+	if _, ok := a.caches[bucket]; !ok {
+		return nil, fmt.Errorf("unknown bucket %q, available buckets: %v", bucket, a.cacheNames())
+	}
+	return []string{bucket}, nil
+}
+
+func formatExpiry(entry cache.EntryInfo) string {
+	if entry.HasExpired() {
+		return "expired"
+	}
+	return formatCacheTime(entry.ExpiresAt, "never")
+}
+
+func formatCacheTime(t time.Time, zero string) string {
+	if t.IsZero() {
+		return zero
+	}
+	return t.Local().Format(time.DateTime)
+}
+
+// formatSaved returns the space saved by compression with its percentage.
+func formatSaved(stats cache.Stats) string {
+	if stats.DecompressedSize == 0 {
+		return formatSize(0)
+	}
+
+	percent := stats.Saved() * 100 / stats.DecompressedSize
+	return fmt.Sprintf("%s (%d%%)", formatSize(stats.Saved()), percent)
+}
+
+func formatSize(size int) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := unit, 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGT"[exp])
+}
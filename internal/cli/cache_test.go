@@ -0,0 +1,171 @@
+package cli_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matthieugusmini/rift/internal/cache"
+	"github.com/matthieugusmini/rift/internal/cli"
+)
+
+func TestApp_Cache(t *testing.T) {
+	t.Run("prints stats of every bucket", func(t *testing.T) {
+		app, stdout := newCacheTestApp(t, map[string][]string{
+			"standings": {"lec-winter", "lck-cup"},
+			"splits":    {"current_splits"},
+		})
+
+		err := app.Run(t.Context(), []string{"cache", "stats"})
+
+		require.NoError(t, err)
+		assert.Regexp(t, `splits\s+1\s+0`, stdout.String())
+		assert.Regexp(t, `standings\s+2\s+0`, stdout.String())
+		assert.Regexp(t, `total\s+3\s+0`, stdout.String())
+	})
+
+	t.Run("lists entries filtered by prefix", func(t *testing.T) {
+		app, stdout := newCacheTestApp(t, map[string][]string{
+			"standings": {"lec-winter", "lck-cup"},
+		})
+
+		err := app.Run(t.Context(), []string{"cache", "ls", "--prefix", "lec"})
+
+		require.NoError(t, err)
+		assert.Contains(t, stdout.String(), "lec-winter")
+		assert.NotContains(t, stdout.String(), "lck-cup")
+	})
+
+	t.Run("purges entries of a bucket", func(t *testing.T) {
+		app, stdout := newCacheTestApp(t, map[string][]string{
+			"standings": {"lec-winter", "lck-cup"},
+			"splits":    {"current_splits"},
+		})
+
+		err := app.Run(t.Context(), []string{"cache", "purge", "--bucket", "standings"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Deleted 2 entries\n", stdout.String())
+	})
+
+	t.Run("purges every bucket but the preserved ones", func(t *testing.T) {
+		app, stdout := newCacheTestApp(t, map[string][]string{
+			"standings":       {"lec-winter", "lck-cup"},
+			"revealedMatches": {"1", "2", "3"},
+		}, cli.WithPreservedBuckets("revealedMatches"))
+
+		err := app.Run(t.Context(), []string{"cache", "purge"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Deleted 2 entries\n", stdout.String())
+	})
+
+	t.Run("purges a preserved bucket selected explicitly", func(t *testing.T) {
+		app, stdout := newCacheTestApp(t, map[string][]string{
+			"standings":       {"lec-winter", "lck-cup"},
+			"revealedMatches": {"1", "2", "3"},
+		}, cli.WithPreservedBuckets("revealedMatches"))
+
+		err := app.Run(t.Context(), []string{"cache", "purge", "--bucket", "revealedMatches"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Deleted 3 entries\n", stdout.String())
+	})
+
+	t.Run("exports and imports entries", func(t *testing.T) {
+		src, _ := newCacheTestApp(t, map[string][]string{
+			"standings": {"lec-winter", "lck-cup"},
+		})
+		path := filepath.Join(t.TempDir(), "export.json")
+		err := src.Run(t.Context(), []string{"cache", "export", "--output", path})
+		require.NoError(t, err)
+		dst, stdout := newCacheTestApp(t, map[string][]string{"standings": nil})
+
+		err = dst.Run(t.Context(), []string{"cache", "import", path})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Imported 2 entries\n", stdout.String())
+	})
+
+	t.Run("skips buckets exported with another schema version", func(t *testing.T) {
+		src, _ := newCacheTestAppWithSchema(t, map[string][]string{
+			"standings": {"lec-winter", "lck-cup"},
+		}, "1")
+		path := filepath.Join(t.TempDir(), "export.json")
+		err := src.Run(t.Context(), []string{"cache", "export", "--output", path})
+		require.NoError(t, err)
+		dst, stdout := newCacheTestAppWithSchema(t, map[string][]string{"standings": nil}, "2")
+
+		err = dst.Run(t.Context(), []string{"cache", "import", path})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Imported 0 entries\n", stdout.String())
+	})
+
+	tt := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing subcommand", args: []string{"cache"}},
+		{name: "unknown subcommand", args: []string{"cache", "warm"}},
+		{name: "unknown bucket", args: []string{"cache", "purge", "--bucket", "players"}},
+		{name: "missing import file", args: []string{"cache", "import"}},
+	}
+	for _, tc := range tt {
+		t.Run("returns error if "+tc.name, func(t *testing.T) {
+			app, _ := newCacheTestApp(t, map[string][]string{"standings": nil})
+
+			err := app.Run(t.Context(), tc.args)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+// newCacheTestApp returns an [cli.App] managing a cache bucket for each key of
+// entries filled with the given keys.
+func newCacheTestApp(
+	t *testing.T,
+	entries map[string][]string,
+	opts ...cli.AppOption,
+) (*cli.App, *bytes.Buffer) {
+	t.Helper()
+
+	return newCacheTestAppWithSchema(t, entries, "1", opts...)
+}
+
+// newCacheTestAppWithSchema is like newCacheTestApp but with the given schema version
+// for every bucket.
+func newCacheTestAppWithSchema(
+	t *testing.T,
+	entries map[string][]string,
+	version string,
+	opts ...cli.AppOption,
+) (*cli.App, *bytes.Buffer) {
+	t.Helper()
+
+	backend := cache.NewMemoryBackend()
+	caches := make(map[string]cli.Cache)
+	for bucket, keys := range entries {
+		_, err := cache.EnsureSchema(backend, bucket, cache.Schema{Version: version})
+		require.NoError(t, err)
+
+		c := cache.New[string](backend, bucket, time.Hour)
+		for _, key := range keys {
+			require.NoError(t, c.Set(key, "Bombardiro Crocodilo"))
+		}
+		caches[bucket] = c
+	}
+
+	var stdout bytes.Buffer
+	opts = append(
+		[]cli.AppOption{cli.WithOutput(&stdout, &bytes.Buffer{}), cli.WithCaches(caches)},
+		opts...,
+	)
+	app := cli.New(newStubLoLEsportsLoader(), newStubBracketTemplateLoader(), opts...)
+	return app, &stdout
+}
@@ -0,0 +1,235 @@
+// Package cli implements the headless commands of Rift which print
+// LoL Esports data to stdout instead of starting the TUI.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/matthieugusmini/go-lolesports"
+
+	"github.com/matthieugusmini/rift/internal/rift"
+)
+
+// LoLEsportsLoader loads LoL Esports data.
+type LoLEsportsLoader interface {
+	GetSchedule(
+		ctx context.Context,
+		opts *lolesports.GetScheduleOptions,
+	) (lolesports.Schedule, error)
+
+	LoadStandingsByTournamentIDs(
+		ctx context.Context,
+		tournamentIDs []string,
+	) ([]lolesports.Standings, error)
+
+	LoadCurrentSeasonSplits(ctx context.Context) ([]lolesports.Split, error)
+}
+
+// BracketTemplateLoader loads bracket templates.
+type BracketTemplateLoader interface {
+	Load(
+		ctx context.Context,
+		stageID string,
+		matches []lolesports.Match,
+	) (rift.BracketTemplate, error)
+}
+
+type command struct {
+	summary string
+	run     func(app *App, ctx context.Context, args []string) error
+}
+
+var commands = map[string]command{
+	"schedule": {
+		summary: "Print the schedule of the upcoming and past matches",
+		run:     (*App).runSchedule,
+	},
+	"standings": {
+		summary: "Print the rankings of a league for a split of the current season",
+		run:     (*App).runStandings,
+	},
+	"bracket": {
+		summary: "Print the bracket of a stage of the current season",
+		run:     (*App).runBracket,
+	},
+	"cache": {
+		summary: "Inspect, purge, export or import the cached data",
+		run:     (*App).runCache,
+	},
+	"template": {
+		summary: "Lint or preview bracket template files",
+		run:     (*App).runTemplate,
+	},
+}
+
+// IsCommand returns true if name is the name of a headless command, false otherwise.
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
+
+// Usage writes the list of the available commands to w.
+func Usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-12s %s\n", name, commands[name].summary)
+	}
+}
+
+// AppOption represents a functional option to customize an [App].
+type AppOption func(*App)
+
+// WithOutput sets the writers used by the [App] for the
+// standard and error outputs.
+func WithOutput(stdout, stderr io.Writer) AppOption {
+	return func(a *App) {
+		a.stdout = stdout
+		a.stderr = stderr
+	}
+}
+
+// WithCaches sets the caches managed by the cache command indexed by bucket name.
+func WithCaches(caches map[string]Cache) AppOption {
+	return func(a *App) {
+		a.caches = caches
+	}
+}
+
+// WithPreservedBuckets sets the buckets holding user state rather than fetched data.
+// They are only purged by the cache command when selected explicitly.
+func WithPreservedBuckets(names ...string) AppOption {
+	return func(a *App) {
+		a.preservedBuckets = names
+	}
+}
+
+// App runs the headless commands.
+type App struct {
+	lolesportsLoader      LoLEsportsLoader
+	bracketTemplateLoader BracketTemplateLoader
+	caches                map[string]Cache
+	preservedBuckets      []string
+
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// New creates a new instance of [App].
+//
+// By default the commands write to [os.Stdout] and [os.Stderr].
+func New(
+	lolesportsLoader LoLEsportsLoader,
+	bracketTemplateLoader BracketTemplateLoader,
+	opts ...AppOption,
+) *App {
+	a := &App{
+		lolesportsLoader:      lolesportsLoader,
+		bracketTemplateLoader: bracketTemplateLoader,
+		stdout:                os.Stdout,
+		stderr:                os.Stderr,
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// Run runs the command named by the first argument with the remaining arguments.
+//
+// An error is returned if the command does not exist or if it fails.
+func (a *App) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("no command provided")
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	err := cmd.run(a, ctx, args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	a.warnIfStale()
+	return nil
+}
+
+// staleReporter is implemented by the loaders able to serve outdated
+// data from the cache when fresh data cannot be fetched.
+type staleReporter interface {
+	StaleSince() (time.Time, bool)
+}
+
+// warnIfStale warns on the error output when the printed data is outdated
+// so that scripts reading the standard output are not affected.
+func (a *App) warnIfStale() {
+	var (
+		oldest  time.Time
+		isStale bool
+	)
+	for _, loader := range []any{a.lolesportsLoader, a.bracketTemplateLoader} {
+		reporter, ok := loader.(staleReporter)
+		if !ok {
+			continue
+		}
+		staleSince, ok := reporter.StaleSince()
+		if ok && (!isStale || staleSince.Before(oldest)) {
+			oldest, isStale = staleSince, true
+		}
+	}
+
+	if isStale {
+		fmt.Fprintf(
+			a.stderr,
+			"Warning: could not fetch fresh data, showing cached data from %s\n",
+			oldest.Local().Format(time.DateTime),
+		)
+	}
+}
+
+// parseInterspersed parses the flags of fs wherever they appear among the positional
+// arguments, e.g. "file.json --flag value", and returns the positional arguments.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func (a *App) newFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: rift %s %s\n\nFlags:\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
@@ -0,0 +1,316 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/matthieugusmini/go-lolesports"
+
+	"github.com/matthieugusmini/rift/internal/rift"
+)
+
+const dateLayout = "2006-01-02"
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+type scheduleOptions struct {
+	leagues  []string
+	from, to time.Time
+	pages    int
+	format   string
+}
+
+func (a *App) runSchedule(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("schedule", "[flags]")
+	var (
+		leagues = fs.String("league", "", "Comma-separated list of league names to keep (e.g. LEC,LCK)")
+		from    = fs.String("from", "", "Keep only the matches starting on or after this date (YYYY-MM-DD)")
+		to      = fs.String("to", "", "Keep only the matches starting on or before this date (YYYY-MM-DD)")
+		pages   = fs.Int(
+			"pages",
+			0,
+			"Maximum number of older and newer pages to fetch around the current one",
+		)
+		format = fs.String("format", formatTable, "Output format: table, json or csv")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := scheduleOptions{
+		pages:  *pages,
+		format: *format,
+	}
+	if *leagues != "" {
+		opts.leagues = strings.Split(*leagues, ",")
+	}
+
+	var err error
+	if opts.from, err = parseDate(*from); err != nil {
+		return fmt.Errorf("invalid --from date: %w", err)
+	}
+	if opts.to, err = parseDate(*to); err != nil {
+		return fmt.Errorf("invalid --to date: %w", err)
+	}
+	if !opts.to.IsZero() {
+		// Include the whole day.
+		opts.to = opts.to.AddDate(0, 0, 1)
+	}
+
+	events, err := a.fetchScheduleEvents(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("could not fetch the schedule: %w", err)
+	}
+
+	matches := filterScheduleMatches(rift.FilterMatchEvents(events), opts)
+
+	switch opts.format {
+	case formatTable:
+		return writeScheduleTable(a.stdout, matches)
+	case formatJSON:
+		return writeScheduleJSON(a.stdout, matches)
+	case formatCSV:
+		return writeScheduleCSV(a.stdout, matches)
+	default:
+		return fmt.Errorf("unknown format %q", opts.format)
+	}
+}
+
+// fetchScheduleEvents fetches the current schedule page and then older and newer
+// pages until the requested date range is covered or the maximum number of pages
+// is reached.
+func (a *App) fetchScheduleEvents(
+	ctx context.Context,
+	opts scheduleOptions,
+) ([]lolesports.Event, error) {
+	schedule, err := a.lolesportsLoader.GetSchedule(ctx, &lolesports.GetScheduleOptions{})
+	if err != nil {
+		return nil, err
+	}
+	events := schedule.Events
+
+	olderPageToken := schedule.Pages.Older
+	for range opts.pages {
+		if olderPageToken == "" || (!opts.from.IsZero() && startsBefore(events, opts.from)) {
+			break
+		}
+
+		schedule, err := a.lolesportsLoader.GetSchedule(
+			ctx,
+			&lolesports.GetScheduleOptions{PageToken: &olderPageToken},
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(schedule.Events, events...)
+		olderPageToken = schedule.Pages.Older
+	}
+
+	newerPageToken := schedule.Pages.Newer
+	for range opts.pages {
+		if newerPageToken == "" || (!opts.to.IsZero() && endsAfter(events, opts.to)) {
+			break
+		}
+
+		schedule, err := a.lolesportsLoader.GetSchedule(
+			ctx,
+			&lolesports.GetScheduleOptions{PageToken: &newerPageToken},
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, schedule.Events...)
+		newerPageToken = schedule.Pages.Newer
+	}
+
+	return events, nil
+}
+
+func filterScheduleMatches(
+	events []lolesports.Event,
+	opts scheduleOptions,
+) []lolesports.Event {
+	return slices.DeleteFunc(events, func(event lolesports.Event) bool {
+		if !opts.from.IsZero() && event.StartTime.Before(opts.from) {
+			return true
+		}
+		if !opts.to.IsZero() && !event.StartTime.Before(opts.to) {
+			return true
+		}
+		if len(opts.leagues) > 0 {
+			return !slices.ContainsFunc(opts.leagues, func(league string) bool {
+				return strings.EqualFold(strings.TrimSpace(league), event.League.Name)
+			})
+		}
+		return false
+	})
+}
+
+func writeScheduleTable(w io.Writer, events []lolesports.Event) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "DATE\tTIME\tLEAGUE\tBLOCK\tMATCH\tSCORE\tFORMAT\tSTATE")
+	for _, event := range events {
+		team1, team2 := event.Match.Teams[0], event.Match.Teams[1]
+		startTime := event.StartTime.Local()
+
+		score := "-"
+		if event.State != lolesports.EventStateUnstarted {
+			score = fmt.Sprintf("%d-%d", gameWins(team1), gameWins(team2))
+		}
+
+		fmt.Fprintf(
+			tw,
+			"%s\t%s\t%s\t%s\t%s vs %s\t%s\t%s\t%s\n",
+			startTime.Format(dateLayout),
+			startTime.Format("15:04"),
+			event.League.Name,
+			event.BlockName,
+			team1.Code,
+			team2.Code,
+			score,
+			formatStrategy(event.Match.Strategy),
+			event.State,
+		)
+	}
+
+	return tw.Flush()
+}
+
+type scheduleMatch struct {
+	ID        string         `json:"id"`
+	StartTime time.Time      `json:"startTime"`
+	State     string         `json:"state"`
+	League    string         `json:"league"`
+	BlockName string         `json:"blockName"`
+	Strategy  string         `json:"strategy"`
+	Teams     []scheduleTeam `json:"teams"`
+}
+
+type scheduleTeam struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	GameWins int    `json:"gameWins"`
+	Outcome  string `json:"outcome,omitempty"`
+}
+
+func writeScheduleJSON(w io.Writer, events []lolesports.Event) error {
+	matches := make([]scheduleMatch, len(events))
+	for i, event := range events {
+		teams := make([]scheduleTeam, len(event.Match.Teams))
+		for j, team := range event.Match.Teams {
+			teams[j] = scheduleTeam{
+				Code:     team.Code,
+				Name:     team.Name,
+				GameWins: gameWins(team),
+				Outcome:  outcome(team),
+			}
+		}
+
+		matches[i] = scheduleMatch{
+			ID:        event.Match.ID,
+			StartTime: event.StartTime,
+			State:     string(event.State),
+			League:    event.League.Name,
+			BlockName: event.BlockName,
+			Strategy:  formatStrategy(event.Match.Strategy),
+			Teams:     teams,
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(matches)
+}
+
+func writeScheduleCSV(w io.Writer, events []lolesports.Event) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{
+		"id",
+		"start_time",
+		"state",
+		"league",
+		"block_name",
+		"strategy",
+		"team1",
+		"team2",
+		"team1_game_wins",
+		"team2_game_wins",
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		team1, team2 := event.Match.Teams[0], event.Match.Teams[1]
+		record := []string{
+			event.Match.ID,
+			event.StartTime.Format(time.RFC3339),
+			string(event.State),
+			event.League.Name,
+			event.BlockName,
+			formatStrategy(event.Match.Strategy),
+			team1.Code,
+			team2.Code,
+			strconv.Itoa(gameWins(team1)),
+			strconv.Itoa(gameWins(team2)),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(dateLayout, s, time.Local)
+}
+
+func startsBefore(events []lolesports.Event, date time.Time) bool {
+	return len(events) > 0 && events[0].StartTime.Before(date)
+}
+
+func endsAfter(events []lolesports.Event, date time.Time) bool {
+	return len(events) > 0 && !events[len(events)-1].StartTime.Before(date)
+}
+
+func gameWins(team lolesports.Team) int {
+	if team.Result == nil {
+		return 0
+	}
+	return team.Result.GameWins
+}
+
+func outcome(team lolesports.Team) string {
+	if team.Result == nil || team.Result.Outcome == nil {
+		return ""
+	}
+	return *team.Result.Outcome
+}
+
+func formatStrategy(strategy lolesports.Strategy) string {
+	switch strategy.Type {
+	case lolesports.MatchStrategyTypeBestOf:
+		return fmt.Sprintf("Bo%d", strategy.Count)
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,118 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matthieugusmini/rift/internal/cli"
+)
+
+func TestApp_Schedule(t *testing.T) {
+	t.Run("prints matches as JSON", func(t *testing.T) {
+		stdout := runCommand(t, newStubLoLEsportsLoader(), "schedule", "--format", "json")
+
+		var got []struct {
+			ID     string `json:"id"`
+			League string `json:"league"`
+			Teams  []struct {
+				Code     string `json:"code"`
+				GameWins int    `json:"gameWins"`
+			} `json:"teams"`
+		}
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &got))
+		require.Len(t, got, 2)
+		assert.Equal(t, "1", got[0].ID)
+		assert.Equal(t, "LEC", got[0].League)
+		assert.Equal(t, "G2", got[0].Teams[0].Code)
+		assert.Equal(t, 3, got[0].Teams[0].GameWins)
+	})
+
+	t.Run("prints matches as CSV", func(t *testing.T) {
+		stdout := runCommand(t, newStubLoLEsportsLoader(), "schedule", "--format", "csv")
+
+		records, err := csv.NewReader(&stdout).ReadAll()
+		require.NoError(t, err)
+		// Header + 2 matches, the show is filtered out.
+		require.Len(t, records, 3)
+		assert.Equal(t, "id", records[0][0])
+		assert.Equal(t, []string{"G2", "FNC", "3", "1"}, records[1][6:])
+	})
+
+	t.Run("filters by league", func(t *testing.T) {
+		stdout := runCommand(
+			t,
+			newStubLoLEsportsLoader(),
+			"schedule", "--format", "csv", "--league", "lck",
+		)
+
+		records, err := csv.NewReader(&stdout).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "LCK", records[1][3])
+	})
+
+	t.Run("fetches older pages until the date range is covered", func(t *testing.T) {
+		loader := newStubLoLEsportsLoader()
+
+		stdout := runCommand(
+			t,
+			loader,
+			"schedule", "--format", "csv", "--from", "2025-01-01", "--pages", "5",
+		)
+
+		records, err := csv.NewReader(&stdout).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 4)
+		assert.Equal(t, "0", records[1][0])
+		assert.Equal(t, 2, loader.calls)
+	})
+
+	t.Run("warns on stderr if the data is stale", func(t *testing.T) {
+		loader := newStubLoLEsportsLoader()
+		loader.staleSince = time.Date(2025, time.January, 18, 12, 0, 0, 0, time.Local)
+		var stdout, stderr bytes.Buffer
+		app := cli.New(
+			loader,
+			newStubBracketTemplateLoader(),
+			cli.WithOutput(&stdout, &stderr),
+		)
+
+		err := app.Run(t.Context(), []string{"schedule", "--format", "csv"})
+
+		require.NoError(t, err)
+		assert.Contains(t, stderr.String(), "2025-01-18 12:00:00")
+		assert.NotContains(t, stdout.String(), "Warning")
+	})
+
+	t.Run("returns error if cannot fetch", func(t *testing.T) {
+		loader := &stubLoLEsportsLoader{err: errors.New("Faker what was that?")}
+		app := cli.New(
+			loader,
+			newStubBracketTemplateLoader(),
+			cli.WithOutput(&bytes.Buffer{}, &bytes.Buffer{}),
+		)
+
+		err := app.Run(t.Context(), []string{"schedule"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("returns error if unknown format", func(t *testing.T) {
+		app := cli.New(
+			newStubLoLEsportsLoader(),
+			newStubBracketTemplateLoader(),
+			cli.WithOutput(&bytes.Buffer{}, &bytes.Buffer{}),
+		)
+
+		err := app.Run(t.Context(), []string{"schedule", "--format", "yaml"})
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,299 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/matthieugusmini/go-lolesports"
+
+	"github.com/matthieugusmini/rift/internal/rift"
+	"github.com/matthieugusmini/rift/internal/timeutil"
+	"github.com/matthieugusmini/rift/internal/ui"
+)
+
+const (
+	formatText     = "text"
+	formatMarkdown = "markdown"
+)
+
+const defaultWidth = 80
+
+type standingsOptions struct {
+	split  string
+	league string
+	stage  string
+	format string
+	width  int
+}
+
+func (a *App) runStandings(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("standings", "--league <name> [flags]")
+	var (
+		split = fs.String(
+			"split",
+			"",
+			"Name of the split of the current season (default to the ongoing split)",
+		)
+		league = fs.String("league", "", "Name of the league (e.g. LEC)")
+		stage  = fs.String(
+			"stage",
+			"",
+			"Name of the stage (default to all the stages with rankings)",
+		)
+		format = fs.String("format", formatText, "Output format: text, markdown or json")
+		width  = fs.Int("width", defaultWidth, "Width of the ranking tables in text format")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *league == "" {
+		fs.Usage()
+		return errors.New("the --league flag is required")
+	}
+
+	return a.printStandings(ctx, standingsOptions{
+		split:  *split,
+		league: *league,
+		stage:  *stage,
+		format: *format,
+		width:  *width,
+	})
+}
+
+func (a *App) printStandings(ctx context.Context, opts standingsOptions) error {
+	splits, err := a.lolesportsLoader.LoadCurrentSeasonSplits(ctx)
+	if err != nil {
+		return fmt.Errorf("could not load the current season splits: %w", err)
+	}
+
+	split, err := findSplit(splits, opts.split)
+	if err != nil {
+		return err
+	}
+
+	league, err := findLeague(rift.ListLeaguesFromTournaments(split.Tournaments), opts.league)
+	if err != nil {
+		return err
+	}
+
+	tournamentIDs := rift.ListTournamentIDsForLeague(split.Tournaments, league.ID)
+	standings, err := a.lolesportsLoader.LoadStandingsByTournamentIDs(ctx, tournamentIDs)
+	if err != nil {
+		return fmt.Errorf("could not load the standings: %w", err)
+	}
+
+	stages, err := findRankingStages(rift.ListStagesFromStandings(standings), opts.stage)
+	if err != nil {
+		return err
+	}
+
+	switch opts.format {
+	case formatText:
+		return writeStandingsText(a.stdout, split, league, stages, opts.width)
+	case formatMarkdown:
+		return writeStandingsMarkdown(a.stdout, split, league, stages)
+	case formatJSON:
+		return writeStandingsJSON(a.stdout, split, league, stages)
+	default:
+		return fmt.Errorf("unknown format %q", opts.format)
+	}
+}
+
+func findSplit(splits []lolesports.Split, name string) (lolesports.Split, error) {
+	for _, split := range splits {
+		if name == "" && timeutil.IsCurrentTimeBetween(split.StartTime, split.EndTime) {
+			return split, nil
+		}
+		if name != "" && (strings.EqualFold(split.Name, name) || split.Slug == name) {
+			return split, nil
+		}
+	}
+
+	if name == "" {
+		return lolesports.Split{}, errors.New("no ongoing split, use --split to choose one")
+	}
+	return lolesports.Split{}, fmt.Errorf(
+		"split %q not found in the current season, available splits: %s",
+		name,
+		joinNames(splits, func(s lolesports.Split) string { return s.Name }),
+	)
+}
+
+func findLeague(leagues []lolesports.League, name string) (lolesports.League, error) {
+	for _, league := range leagues {
+		if strings.EqualFold(league.Name, name) || league.ID == name {
+			return league, nil
+		}
+	}
+
+	return lolesports.League{}, fmt.Errorf(
+		"league %q not found in the split, available leagues: %s",
+		name,
+		joinNames(leagues, func(l lolesports.League) string { return l.Name }),
+	)
+}
+
+func findRankingStages(stages []lolesports.Stage, name string) ([]lolesports.Stage, error) {
+	if name == "" {
+		var rankingStages []lolesports.Stage
+		for _, stage := range stages {
+			if !rift.IsBracketStage(stage) {
+				rankingStages = append(rankingStages, stage)
+			}
+		}
+		if len(rankingStages) == 0 {
+			return nil, errors.New("no stage with rankings found for this league")
+		}
+		return rankingStages, nil
+	}
+
+	for _, stage := range stages {
+		if !strings.EqualFold(stage.Name, name) && stage.Slug != name {
+			continue
+		}
+		if rift.IsBracketStage(stage) {
+			return nil, fmt.Errorf("stage %q is a bracket stage and has no rankings", name)
+		}
+		return []lolesports.Stage{stage}, nil
+	}
+
+	return nil, fmt.Errorf(
+		"stage %q not found, available stages: %s",
+		name,
+		joinNames(stages, func(s lolesports.Stage) string { return s.Name }),
+	)
+}
+
+func writeStandingsText(
+	w io.Writer,
+	split lolesports.Split,
+	league lolesports.League,
+	stages []lolesports.Stage,
+	width int,
+) error {
+	fmt.Fprintf(w, "%s: %s Standings\n", split.Name, league.Name)
+	for _, stage := range stages {
+		fmt.Fprintf(w, "\n%s\n\n%s\n", stage.Name, ui.RenderRankings(stage, width))
+	}
+	return nil
+}
+
+func writeStandingsMarkdown(
+	w io.Writer,
+	split lolesports.Split,
+	league lolesports.League,
+	stages []lolesports.Stage,
+) error {
+	fmt.Fprintf(w, "# %s: %s Standings\n", split.Name, league.Name)
+	for _, stage := range stages {
+		fmt.Fprintf(w, "\n## %s\n", stage.Name)
+		for _, section := range stage.Sections {
+			fmt.Fprintf(w, "\n### %s\n\n", section.Name)
+			fmt.Fprintln(w, "| Ranking | Team | Series Win / Loss | Win / Loss % |")
+			fmt.Fprintln(w, "|--------:|:-----|:-----------------:|-------------:|")
+			for _, row := range newStandingsRows(section.Rankings) {
+				fmt.Fprintf(
+					w,
+					"| %d | %s | %dW - %dL | %d%% |\n",
+					row.Ordinal,
+					row.Team,
+					row.Wins,
+					row.Losses,
+					row.Winrate,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+type standingsOutput struct {
+	Split  string        `json:"split"`
+	League string        `json:"league"`
+	Stages []stageOutput `json:"stages"`
+}
+
+type stageOutput struct {
+	ID       string          `json:"id"`
+	Name     string          `json:"name"`
+	Sections []sectionOutput `json:"sections"`
+}
+
+type sectionOutput struct {
+	Name     string         `json:"name"`
+	Rankings []standingsRow `json:"rankings"`
+}
+
+type standingsRow struct {
+	Ordinal int    `json:"ordinal"`
+	Team    string `json:"team"`
+	Wins    int    `json:"wins"`
+	Losses  int    `json:"losses"`
+	Winrate int    `json:"winrate"`
+}
+
+func writeStandingsJSON(
+	w io.Writer,
+	split lolesports.Split,
+	league lolesports.League,
+	stages []lolesports.Stage,
+) error {
+	out := standingsOutput{
+		Split:  split.Name,
+		League: league.Name,
+		Stages: make([]stageOutput, len(stages)),
+	}
+	for i, stage := range stages {
+		sections := make([]sectionOutput, len(stage.Sections))
+		for j, section := range stage.Sections {
+			sections[j] = sectionOutput{
+				Name:     section.Name,
+				Rankings: newStandingsRows(section.Rankings),
+			}
+		}
+		out.Stages[i] = stageOutput{
+			ID:       stage.ID,
+			Name:     stage.Name,
+			Sections: sections,
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func newStandingsRows(rankings []lolesports.Ranking) []standingsRow {
+	rows := []standingsRow{}
+	for _, ranking := range rankings {
+		for _, team := range ranking.Teams {
+			var wins, losses int
+			if team.Record != nil {
+				wins, losses = team.Record.Wins, team.Record.Losses
+			}
+
+			rows = append(rows, standingsRow{
+				Ordinal: ranking.Ordinal,
+				Team:    team.Code,
+				Wins:    wins,
+				Losses:  losses,
+				Winrate: rift.CalculateWinrate(wins, losses),
+			})
+		}
+	}
+	return rows
+}
+
+func joinNames[T any](values []T, name func(T) string) string {
+	names := make([]string, len(values))
+	for i, v := range values {
+		names[i] = strconv.Quote(name(v))
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,93 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matthieugusmini/rift/internal/cli"
+)
+
+func TestApp_Standings(t *testing.T) {
+	t.Run("prints rankings of the current split as JSON", func(t *testing.T) {
+		stdout := runCommand(
+			t,
+			newStubLoLEsportsLoader(),
+			"standings", "--league", "lec", "--format", "json",
+		)
+
+		var got struct {
+			Split  string `json:"split"`
+			League string `json:"league"`
+			Stages []struct {
+				Name     string `json:"name"`
+				Sections []struct {
+					Rankings []struct {
+						Ordinal int    `json:"ordinal"`
+						Team    string `json:"team"`
+						Winrate int    `json:"winrate"`
+					} `json:"rankings"`
+				} `json:"sections"`
+			} `json:"stages"`
+		}
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &got))
+		assert.Equal(t, "Winter", got.Split)
+		assert.Equal(t, "LEC", got.League)
+		// The bracket stage is skipped.
+		require.Len(t, got.Stages, 1)
+		rankings := got.Stages[0].Sections[0].Rankings
+		require.Len(t, rankings, 2)
+		assert.Equal(t, "G2", rankings[0].Team)
+		assert.Equal(t, 88, rankings[0].Winrate)
+	})
+
+	t.Run("prints rankings as Markdown", func(t *testing.T) {
+		stdout := runCommand(
+			t,
+			newStubLoLEsportsLoader(),
+			"standings",
+			"--split", "winter",
+			"--league", "LEC",
+			"--stage", "Regular Season",
+			"--format", "markdown",
+		)
+
+		assert.Contains(t, stdout.String(), "# Winter: LEC Standings")
+		assert.Contains(t, stdout.String(), "| 2 | FNC | 6W - 3L | 66% |")
+	})
+
+	t.Run("prints rankings as text", func(t *testing.T) {
+		stdout := runCommand(t, newStubLoLEsportsLoader(), "standings", "--league", "LEC")
+
+		assert.Contains(t, stdout.String(), "Regular Season")
+		assert.Contains(t, stdout.String(), "8W - 1L")
+	})
+
+	tt := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing league", args: []string{"standings"}},
+		{name: "unknown split", args: []string{"standings", "--split", "Summer", "--league", "LEC"}},
+		{name: "unknown league", args: []string{"standings", "--league", "LFL"}},
+		{name: "unknown stage", args: []string{"standings", "--league", "LEC", "--stage", "Groups"}},
+		{name: "bracket stage", args: []string{"standings", "--league", "LEC", "--stage", "Playoffs"}},
+		{name: "no stage with rankings", args: []string{"standings", "--league", "LCK"}},
+	}
+	for _, tc := range tt {
+		t.Run("returns error if "+tc.name, func(t *testing.T) {
+			app := cli.New(
+				newStubLoLEsportsLoader(),
+				newStubBracketTemplateLoader(),
+				cli.WithOutput(&bytes.Buffer{}, &bytes.Buffer{}),
+			)
+
+			err := app.Run(t.Context(), tc.args)
+
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matthieugusmini/go-lolesports"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matthieugusmini/rift/internal/cli"
+	"github.com/matthieugusmini/rift/internal/rift"
+)
+
+func runCommand(t *testing.T, loader cli.LoLEsportsLoader, args ...string) bytes.Buffer {
+	t.Helper()
+
+	var stdout bytes.Buffer
+	app := cli.New(
+		loader,
+		newStubBracketTemplateLoader(),
+		cli.WithOutput(&stdout, &bytes.Buffer{}),
+	)
+
+	err := app.Run(t.Context(), args)
+	require.NoError(t, err)
+
+	return stdout
+}
+
+const olderPageToken = "older"
+
+type stubLoLEsportsLoader struct {
+	schedules  map[string]lolesports.Schedule
+	splits     []lolesports.Split
+	standings  map[string][]lolesports.Standings
+	staleSince time.Time
+	err        error
+	calls      int
+}
+
+func newStubLoLEsportsLoader() *stubLoLEsportsLoader {
+	return &stubLoLEsportsLoader{
+		schedules: map[string]lolesports.Schedule{
+			"": {
+				Pages: lolesports.Pages{Older: olderPageToken},
+				Events: []lolesports.Event{
+					newTestEvent("1", "LEC", time.Date(2025, time.January, 18, 17, 0, 0, 0, time.UTC)),
+					{Type: lolesports.EventTypeShow},
+					newTestEvent("2", "LCK", time.Date(2025, time.January, 19, 8, 0, 0, 0, time.UTC)),
+				},
+			},
+			olderPageToken: {
+				Events: []lolesports.Event{
+					newTestEvent("0", "LEC", time.Date(2025, time.January, 1, 17, 0, 0, 0, time.UTC)),
+				},
+			},
+		},
+		splits: []lolesports.Split{
+			{
+				Name:      "Winter",
+				StartTime: time.Now().AddDate(0, -1, 0),
+				EndTime:   time.Now().AddDate(0, 1, 0),
+				Tournaments: []lolesports.Tournament{
+					{ID: "lec-winter", League: lolesports.League{ID: "lec", Name: "LEC"}},
+					{ID: "lck-cup", League: lolesports.League{ID: "lck", Name: "LCK"}},
+				},
+			},
+		},
+		standings: map[string][]lolesports.Standings{
+			"lec-winter": {{Stages: []lolesports.Stage{testGroupStage, testBracketStage}}},
+		},
+	}
+}
+
+func (l *stubLoLEsportsLoader) GetSchedule(
+	_ context.Context,
+	opts *lolesports.GetScheduleOptions,
+) (lolesports.Schedule, error) {
+	l.calls++
+	if l.err != nil {
+		return lolesports.Schedule{}, l.err
+	}
+
+	var pageToken string
+	if opts != nil && opts.PageToken != nil {
+		pageToken = *opts.PageToken
+	}
+	return l.schedules[pageToken], nil
+}
+
+func (l *stubLoLEsportsLoader) LoadStandingsByTournamentIDs(
+	_ context.Context,
+	tournamentIDs []string,
+) ([]lolesports.Standings, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+
+	var standings []lolesports.Standings
+	for _, id := range tournamentIDs {
+		standings = append(standings, l.standings[id]...)
+	}
+	return standings, nil
+}
+
+func (l *stubLoLEsportsLoader) LoadCurrentSeasonSplits(
+	_ context.Context,
+) ([]lolesports.Split, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.splits, nil
+}
+
+func (l *stubLoLEsportsLoader) StaleSince() (time.Time, bool) {
+	return l.staleSince, !l.staleSince.IsZero()
+}
+
+var testGroupStage = lolesports.Stage{
+	ID:   "regular",
+	Name: "Regular Season",
+	Slug: "regular_season",
+	Sections: []lolesports.Section{
+		{
+			Name: "Regular Season",
+			Rankings: []lolesports.Ranking{
+				{
+					Ordinal: 1,
+					Teams: []lolesports.Team{
+						{Code: "G2", Record: &lolesports.Record{Wins: 8, Losses: 1}},
+					},
+				},
+				{
+					Ordinal: 2,
+					Teams: []lolesports.Team{
+						{Code: "FNC", Record: &lolesports.Record{Wins: 6, Losses: 3}},
+					},
+				},
+			},
+		},
+	},
+}
+
+var testBracketStage = lolesports.Stage{
+	ID:   "playoffs",
+	Name: "Playoffs",
+	Slug: "playoffs",
+	Sections: []lolesports.Section{
+		{
+			Name: "Bracket",
+			Matches: []lolesports.Match{
+				newTestEvent("3", "LEC", time.Now()).Match,
+			},
+		},
+	},
+}
+
+type stubBracketTemplateLoader struct {
+	templates map[string]rift.BracketTemplate
+}
+
+func newStubBracketTemplateLoader() *stubBracketTemplateLoader {
+	return &stubBracketTemplateLoader{
+		templates: map[string]rift.BracketTemplate{
+			testBracketStage.ID: {
+				Rounds: []rift.Round{
+					{
+						Title:   "Final",
+						Matches: []rift.Match{{DisplayType: rift.DisplayTypeMatch}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (l *stubBracketTemplateLoader) Load(
+	_ context.Context,
+	stageID string,
+	_ []lolesports.Match,
+) (rift.BracketTemplate, error) {
+	tmpl, ok := l.templates[stageID]
+	if !ok {
+		return rift.BracketTemplate{}, errors.New("template not found")
+	}
+	return tmpl, nil
+}
+
+func newTestEvent(id, league string, startTime time.Time) lolesports.Event {
+	return lolesports.Event{
+		StartTime: startTime,
+		BlockName: "Week 1",
+		State:     lolesports.EventStateCompleted,
+		Type:      lolesports.EventTypeMatch,
+		League:    lolesports.League{Name: league},
+		Match: lolesports.Match{
+			ID: id,
+			Teams: []lolesports.Team{
+				{Code: "G2", Result: &lolesports.Result{Outcome: pointer("win"), GameWins: 3}},
+				{Code: "FNC", Result: &lolesports.Result{Outcome: pointer("loss"), GameWins: 1}},
+			},
+			Strategy: lolesports.Strategy{Type: lolesports.MatchStrategyTypeBestOf, Count: 5},
+		},
+	}
+}
+
+func pointer[T any](v T) *T { return &v }
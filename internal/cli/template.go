@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/matthieugusmini/go-lolesports"
+
+	"github.com/matthieugusmini/rift/internal/rift"
+	"github.com/matthieugusmini/rift/internal/ui"
+)
+
+var templateSubcommands = map[string]func(app *App, args []string) error{
+	"lint":    (*App).runTemplateLint,
+	"preview": (*App).runTemplatePreview,
+}
+
+func (a *App) runTemplate(_ context.Context, args []string) error {
+	usage := func() {
+		fmt.Fprintln(a.stderr, "Usage: rift template <lint|preview> <file> [flags]")
+	}
+
+	if len(args) == 0 {
+		usage()
+		return errors.New("no template command provided")
+	}
+	if args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage()
+		return nil
+	}
+
+	run, ok := templateSubcommands[args[0]]
+	if !ok {
+		usage()
+		return fmt.Errorf("unknown template command %q", args[0])
+	}
+
+	return run(a, args[1:])
+}
+
+func (a *App) runTemplateLint(args []string) error {
+	fs := a.newFlagSet("template lint", "<file>... [flags]")
+	matches := fs.Int(
+		"matches",
+		0,
+		"Number of matches of the stage (default to the number of match slots of each file)",
+	)
+	files, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		fs.Usage()
+		return errors.New("the template file is required")
+	}
+
+	var invalid int
+	for _, path := range files {
+		if err := lintTemplateFile(path, *matches); err != nil {
+			// Print each problem on its own line prefixed by the file like a compiler would.
+			problems := strings.TrimPrefix(err.Error(), rift.ErrInvalidBracketTemplate.Error()+": ")
+			for _, problem := range strings.Split(problems, "\n") {
+				fmt.Fprintf(a.stdout, "%s: %s\n", path, problem)
+			}
+			invalid++
+			continue
+		}
+		fmt.Fprintf(a.stdout, "%s: ok\n", path)
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d templates are invalid", invalid, len(files))
+	}
+	return nil
+}
+
+func lintTemplateFile(path string, matchCount int) error {
+	tmpl, err := readTemplateFile(path)
+	if err != nil {
+		return err
+	}
+
+	if matchCount <= 0 {
+		matchCount = tmpl.MatchSlots()
+	}
+	return tmpl.Validate(matchCount)
+}
+
+func (a *App) runTemplatePreview(args []string) error {
+	fs := a.newFlagSet("template preview", "<file> [flags]")
+	var (
+		matches = fs.Int(
+			"matches",
+			0,
+			"Number of placeholder matches (default to the number of match slots of the template)",
+		)
+		width   = fs.Int("width", 0, "Width used to center the bracket (default to its own width)")
+		noColor = fs.Bool("no-color", false, "Strip the ANSI escape sequences from the output")
+	)
+	files, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(files) != 1 {
+		fs.Usage()
+		return errors.New("a single template file is required")
+	}
+
+	tmpl, err := readTemplateFile(files[0])
+	if err != nil {
+		return err
+	}
+
+	matchCount := *matches
+	if matchCount <= 0 {
+		matchCount = tmpl.MatchSlots()
+	}
+	if err := tmpl.Validate(matchCount); err != nil {
+		return err
+	}
+
+	bracket := ui.RenderBracket(tmpl, placeholderMatches(matchCount), *width)
+	if *noColor {
+		bracket = ansi.Strip(bracket)
+	}
+
+	_, err = fmt.Fprintln(a.stdout, bracket)
+	return err
+}
+
+// readTemplateFile decodes the bracket template stored at path.
+//
+// Unknown fields are rejected so that typos in the field names are reported.
+func readTemplateFile(path string) (rift.BracketTemplate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return rift.BracketTemplate{}, fmt.Errorf("could not open the template file: %w", err)
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+
+	var tmpl rift.BracketTemplate
+	if err := dec.Decode(&tmpl); err != nil {
+		return rift.BracketTemplate{}, fmt.Errorf("could not decode the template file: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// placeholderMatches returns n matches whose teams are named after the position
+// of the match in the template, e.g. "M1A" and "M1B" for the first match.
+func placeholderMatches(n int) []lolesports.Match {
+	matches := make([]lolesports.Match, n)
+	for i := range matches {
+		matches[i].Teams = []lolesports.Team{
+			{Code: fmt.Sprintf("M%dA", i+1)},
+			{Code: fmt.Sprintf("M%dB", i+1)},
+		}
+	}
+	return matches
+}
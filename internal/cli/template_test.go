@@ -0,0 +1,121 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matthieugusmini/rift/internal/cli"
+)
+
+const testTemplateJSON = `{
+	"rounds": [
+		{"title": "Semis", "matches": [{"displayType": "match"}, {"displayType": "match"}]},
+		{"title": "Final", "matches": [{"displayType": "match"}]}
+	]
+}`
+
+func TestApp_Template(t *testing.T) {
+	t.Run("lints valid template", func(t *testing.T) {
+		path := writeTemplateFile(t, testTemplateJSON)
+
+		stdout := runCommand(t, newStubLoLEsportsLoader(), "template", "lint", path)
+
+		assert.Equal(t, path+": ok\n", stdout.String())
+	})
+
+	t.Run("lints template against match count", func(t *testing.T) {
+		path := writeTemplateFile(t, testTemplateJSON)
+		app, stdout := newTemplateTestApp()
+
+		err := app.Run(t.Context(), []string{"template", "lint", path, "--matches", "7"})
+
+		require.Error(t, err)
+		assert.Contains(t, stdout.String(), "3 match slots for 7 matches")
+	})
+
+	t.Run("reports every problem of invalid template", func(t *testing.T) {
+		path := writeTemplateFile(t, `{"rounds": [{
+			"links": [{"type": "diagonal"}],
+			"matches": [{"displayType": "match", "above": -1}]
+		}]}`)
+		app, stdout := newTemplateTestApp()
+
+		err := app.Run(t.Context(), []string{"template", "lint", path})
+
+		require.Error(t, err)
+		assert.Contains(t, stdout.String(), path+`: round 1 (""): link 1: unknown type "diagonal"`)
+		assert.Contains(t, stdout.String(), path+`: round 1 (""): match 1: negative above -1`)
+	})
+
+	t.Run("previews template with placeholder matches", func(t *testing.T) {
+		path := writeTemplateFile(t, testTemplateJSON)
+
+		stdout := runCommand(
+			t,
+			newStubLoLEsportsLoader(),
+			"template", "preview", path, "--no-color",
+		)
+
+		got := stdout.String()
+		assert.Contains(t, got, "Semis")
+		assert.Contains(t, got, "Final")
+		assert.Contains(t, got, "M1A")
+		assert.Contains(t, got, "M3B")
+	})
+
+	tt := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing subcommand", args: []string{"template"}},
+		{name: "unknown subcommand", args: []string{"template", "format"}},
+		{name: "missing lint file", args: []string{"template", "lint"}},
+		{name: "missing preview file", args: []string{"template", "preview"}},
+		{name: "unknown field", args: []string{"template", "lint", "{{unknown}}"}},
+		{name: "too many placeholder matches", args: []string{
+			"template", "preview", "{{valid}}", "--matches", "4",
+		}},
+	}
+	for _, tc := range tt {
+		t.Run("returns error if "+tc.name, func(t *testing.T) {
+			files := map[string]string{
+				"{{valid}}":   writeTemplateFile(t, testTemplateJSON),
+				"{{unknown}}": writeTemplateFile(t, `{"round": []}`),
+			}
+			for i, arg := range tc.args {
+				if path, ok := files[arg]; ok {
+					tc.args[i] = path
+				}
+			}
+			app, _ := newTemplateTestApp()
+
+			err := app.Run(t.Context(), tc.args)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func newTemplateTestApp() (*cli.App, *bytes.Buffer) {
+	var stdout bytes.Buffer
+	app := cli.New(
+		newStubLoLEsportsLoader(),
+		newStubBracketTemplateLoader(),
+		cli.WithOutput(&stdout, &bytes.Buffer{}),
+	)
+	return app, &stdout
+}
+
+func writeTemplateFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "template.json")
+	err := os.WriteFile(path, []byte(content), 0o600)
+	require.NoError(t, err)
+	return path
+}
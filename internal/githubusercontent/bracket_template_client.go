@@ -4,8 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"time"
+
+	"golang.org/x/sync/singleflight"
 
 	"github.com/matthieugusmini/rift/internal/rift"
 )
@@ -14,6 +18,9 @@ const (
 	baseURL = "https://raw.githubusercontent.com/matthieugusmini/lolesports-bracket-templates/refs/heads/main"
 
 	bracketTypeByStageIDFilename = "bracket-type-by-stage-id.json"
+
+	// downloadTimeout is the maximum duration of a download shared by concurrent callers.
+	downloadTimeout = time.Minute
 )
 
 type BracketTemplateClientOption func(*BracketTemplateClient)
@@ -24,13 +31,42 @@ func WithBaseURL(url string) BracketTemplateClientOption {
 	}
 }
 
+// WithResponseCache sets the cache storing the downloaded files along with their validators
+// so that they are only downloaded again if they changed.
+func WithResponseCache(cache ResponseCache) BracketTemplateClientOption {
+	return func(c *BracketTemplateClient) {
+		c.responseCache = cache
+	}
+}
+
+// CachedResponse represents a file downloaded from GitHub along with
+// the validators used to revalidate it with a conditional request.
+type CachedResponse struct {
+	Body         json.RawMessage `json:"body"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"lastModified,omitempty"`
+}
+
+// ResponseCache represents a cache storing the files downloaded from GitHub by URL.
+type ResponseCache interface {
+	GetStale(key string) (CachedResponse, time.Time, bool, error)
+	Set(key string, value CachedResponse) error
+}
+
 // BracketTemplateClient handles fetching bracket templates from JSON config files
 // stored in a GitHub repository.
 //
 // Example: https://raw.githubusercontent.com/matthieugusmini/lolesports-bracket-templates/refs/heads/main/8SE.json
+//
+// Concurrent downloads of the same file share a single HTTP request.
+// If a [ResponseCache] is set, files which did not change since they were cached
+// are not downloaded again.
 type BracketTemplateClient struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL       string
+	httpClient    *http.Client
+	responseCache ResponseCache
+	// Deduplicates the identical downloads in flight.
+	group singleflight.Group
 }
 
 // NewBracketTemplateClient creates a new instance of [BracketTemplateClient].
@@ -91,7 +127,7 @@ func (c *BracketTemplateClient) GetTemplateByStageID(
 
 	bracketType, ok := bracketTypeByStageID[stageID]
 	if !ok {
-		return rift.BracketTemplate{}, fmt.Errorf("stage ID %q is not supported", stageID)
+		return rift.BracketTemplate{}, fmt.Errorf("%w: %q", rift.ErrStageNotSupported, stageID)
 	}
 
 	bracketTypeFilename := bracketType + ".json"
@@ -124,20 +160,98 @@ func (c *BracketTemplateClient) getBracketTemplateMapper(
 }
 
 func (c *BracketTemplateClient) get(ctx context.Context, url string, data any) error {
+	// The download is not canceled along with the context of the caller which
+	// started it since the other callers may still be waiting for it.
+	ch := c.group.DoChan(url, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), downloadTimeout)
+		defer cancel()
+
+		return c.download(ctx, url)
+	})
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return res.Err
+		}
+		// The body is shared between the concurrent callers so each of them
+		// decodes its own copy of the data.
+		return json.Unmarshal(res.Val.([]byte), data)
+	}
+}
+
+// download returns the content of the file at url.
+//
+// If the file is present in the response cache, it is revalidated with a conditional request
+// and the cached content is returned if the server responds that it did not change.
+// Errors returned by the response cache are ignored, the file is downloaded again instead.
+func (c *BracketTemplateClient) download(ctx context.Context, url string) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return fmt.Errorf("could not create new request: %w", err)
+		return nil, fmt.Errorf("could not create new request: %w", err)
+	}
+
+	cached, ok := c.getCachedResponse(url)
+	if ok {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && ok {
+		return cached.Body, nil
+	}
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response body: %w", err)
+	}
+
+	c.setCachedResponse(url, body, resp.Header)
+
+	return body, nil
+}
+
+func (c *BracketTemplateClient) getCachedResponse(url string) (CachedResponse, bool) {
+	if c.responseCache == nil {
+		return CachedResponse{}, false
+	}
+
+	// The validators are still valid once the entry has expired.
+	cached, _, ok, err := c.responseCache.GetStale(url)
+	if err != nil || !ok {
+		return CachedResponse{}, false
+	}
+	return cached, cached.ETag != "" || cached.LastModified != ""
+}
+
+func (c *BracketTemplateClient) setCachedResponse(url string, body []byte, header http.Header) {
+	if c.responseCache == nil {
+		return
+	}
+
+	cached := CachedResponse{
+		Body:         body,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+	}
+	if (cached.ETag == "" && cached.LastModified == "") || !json.Valid(body) {
+		return
 	}
 
-	return json.NewDecoder(resp.Body).Decode(data)
+	_ = c.responseCache.Set(url, cached)
 }
@@ -1,11 +1,15 @@
 package githubusercontent_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/matthieugusmini/rift/internal/githubusercontent"
 	"github.com/matthieugusmini/rift/internal/rift"
@@ -126,8 +130,128 @@ func TestBracketTemplateClient_GetTemplateByStageID(t *testing.T) {
 	})
 }
 
+func TestBracketTemplateClient_CoalescesConcurrentDownloads(t *testing.T) {
+	client, mux := setup(t)
+	var mapperCalls atomic.Int32
+	release := make(chan struct{})
+	mux.HandleFunc(
+		"/bracket-type-by-stage-id.json",
+		func(w http.ResponseWriter, r *http.Request) {
+			mapperCalls.Add(1)
+			<-release
+
+			json.NewEncoder(w).Encode(testBracketTypeByStageID)
+		},
+	)
+	mux.HandleFunc("/8SE.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(testBracketTemplate)
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, err := client.ListAvailableStageIDs(t.Context())
+		assert.NoError(t, err)
+	}()
+	go func() {
+		defer wg.Done()
+		_, err := client.GetTemplateByStageID(t.Context(), "1")
+		assert.NoError(t, err)
+	}()
+	// Give the time to both calls to wait for the download in flight.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.EqualValues(t, 1, mapperCalls.Load())
+}
+
+func TestBracketTemplateClient_CoalescedDownloadOutlivesCanceledCaller(t *testing.T) {
+	client, mux := setup(t)
+	release := make(chan struct{})
+	mux.HandleFunc(
+		"/bracket-type-by-stage-id.json",
+		func(w http.ResponseWriter, r *http.Request) {
+			<-release
+
+			json.NewEncoder(w).Encode(testBracketTypeByStageID)
+		},
+	)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	firstErr := make(chan error)
+	go func() {
+		_, err := client.ListAvailableStageIDs(ctx)
+		firstErr <- err
+	}()
+	// Give the time to the first call to start the download.
+	time.Sleep(50 * time.Millisecond)
+	secondErr := make(chan error)
+	go func() {
+		_, err := client.ListAvailableStageIDs(t.Context())
+		secondErr <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-firstErr, context.Canceled)
+	close(release)
+
+	assert.NoError(t, <-secondErr)
+}
+
+func TestBracketTemplateClient_RevalidatesCachedResponses(t *testing.T) {
+	const etag = `"v1"`
+	responseCache := newFakeResponseCache()
+	client, mux := setup(t, githubusercontent.WithResponseCache(responseCache))
+	var fullResponses int
+	mux.HandleFunc(
+		"/bracket-type-by-stage-id.json",
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			fullResponses++
+			w.Header().Set("ETag", etag)
+			json.NewEncoder(w).Encode(testBracketTypeByStageID)
+		},
+	)
+
+	first, err := client.ListAvailableStageIDs(t.Context())
+	require.NoError(t, err)
+	second, err := client.ListAvailableStageIDs(t.Context())
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, first, second)
+	assert.Equal(t, 1, fullResponses)
+	assert.Len(t, responseCache.entries, 1)
+}
+
+type fakeResponseCache struct {
+	entries map[string]githubusercontent.CachedResponse
+}
+
+func newFakeResponseCache() *fakeResponseCache {
+	return &fakeResponseCache{entries: map[string]githubusercontent.CachedResponse{}}
+}
+
+func (c *fakeResponseCache) GetStale(
+	key string,
+) (githubusercontent.CachedResponse, time.Time, bool, error) {
+	v, ok := c.entries[key]
+	return v, time.Now(), ok, nil
+}
+
+func (c *fakeResponseCache) Set(key string, value githubusercontent.CachedResponse) error {
+	c.entries[key] = value
+	return nil
+}
+
 func setup(
 	t *testing.T,
+	opts ...githubusercontent.BracketTemplateClientOption,
 ) (*githubusercontent.BracketTemplateClient, *http.ServeMux) {
 	t.Helper()
 
@@ -136,10 +260,8 @@ func setup(
 	srv := httptest.NewServer(mux)
 	t.Cleanup(srv.Close)
 
-	client := githubusercontent.NewBracketTemplateClient(
-		http.DefaultClient,
-		githubusercontent.WithBaseURL(srv.URL),
-	)
+	opts = append(opts, githubusercontent.WithBaseURL(srv.URL))
+	client := githubusercontent.NewBracketTemplateClient(http.DefaultClient, opts...)
 
 	return client, mux
 }
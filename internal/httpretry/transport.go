@@ -0,0 +1,288 @@
+// Package httpretry provides an [http.RoundTripper] retrying the requests
+// which failed because of a transient error.
+package httpretry
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxAttempts    = 4
+	defaultBaseDelay      = 250 * time.Millisecond
+	defaultMaxDelay       = 5 * time.Second
+	defaultMaxElapsedTime = 30 * time.Second
+
+	// Maximum number of bytes read from the body of a failed response
+	// so that the connection can be reused by the next attempt.
+	maxDrainSize = 64 * 1024
+)
+
+// Option represents a functional option to customize a [Transport].
+type Option func(*Transport)
+
+// WithMaxAttempts sets the maximum number of attempts made for a request,
+// including the first one.
+func WithMaxAttempts(n int) Option {
+	return func(t *Transport) {
+		t.maxAttempts = n
+	}
+}
+
+// WithBackoff sets the delay before the first retry and the maximum delay between two attempts.
+// The delay is doubled after each attempt and a random jitter is applied to it.
+func WithBackoff(baseDelay, maxDelay time.Duration) Option {
+	return func(t *Transport) {
+		t.baseDelay = baseDelay
+		t.maxDelay = maxDelay
+	}
+}
+
+// WithMaxElapsedTime sets the maximum time spent on a request across all its attempts.
+// No retry is made once it would be exceeded and the attempt in flight is canceled
+// when it is.
+func WithMaxElapsedTime(d time.Duration) Option {
+	return func(t *Transport) {
+		t.maxElapsedTime = d
+	}
+}
+
+// WithAttemptTimeout sets the maximum duration of a single attempt,
+// including reading the body of the response.
+//
+// If timeout == 0 the attempts are only bounded by the context of the request.
+func WithAttemptTimeout(timeout time.Duration) Option {
+	return func(t *Transport) {
+		t.attemptTimeout = timeout
+	}
+}
+
+// Transport is an [http.RoundTripper] retrying with an exponential backoff the requests
+// which failed because of a network error, a timeout or a server error.
+//
+// The Retry-After header sent along with 429 and 503 responses is honored.
+// Only idempotent requests, or those carrying an Idempotency-Key header,
+// whose body can be replayed are retried.
+type Transport struct {
+	next           http.RoundTripper
+	maxAttempts    int
+	baseDelay      time.Duration
+	maxDelay       time.Duration
+	maxElapsedTime time.Duration
+	attemptTimeout time.Duration
+	logger         *slog.Logger
+}
+
+// NewTransport returns a new instance of a [Transport] sending the requests with next.
+//
+// If next is nil, [http.DefaultTransport] is used.
+func NewTransport(next http.RoundTripper, logger *slog.Logger, opts ...Option) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	t := &Transport{
+		next:           next,
+		maxAttempts:    defaultMaxAttempts,
+		baseDelay:      defaultBaseDelay,
+		maxDelay:       defaultMaxDelay,
+		maxElapsedTime: defaultMaxElapsedTime,
+		logger:         logger,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		attemptReq, cancel, err := t.newAttemptRequest(req, attempt, start)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+		if err == nil {
+			// The attempt context must live as long as the body is being read.
+			resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+		} else {
+			cancel()
+		}
+
+		if attempt >= t.maxAttempts ||
+			!isIdempotent(req) ||
+			!isReplayable(req) ||
+			!shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		if retryAfter, ok := parseRetryAfter(resp); ok {
+			delay = retryAfter
+		}
+		if time.Since(start)+delay > t.maxElapsedTime {
+			return resp, err
+		}
+
+		t.logger.Debug(
+			"Retrying request",
+			slog.String("url", req.URL.String()),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.Any("err", err),
+			slog.Int("statusCode", statusCode(resp)),
+		)
+
+		if resp != nil {
+			drainAndClose(resp.Body)
+		}
+
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *Transport) newAttemptRequest(
+	req *http.Request,
+	attempt int,
+	start time.Time,
+) (*http.Request, context.CancelFunc, error) {
+	// The attempt must not outlive the time left for the request.
+	timeout := t.maxElapsedTime - time.Since(start)
+	if t.attemptTimeout > 0 {
+		timeout = min(timeout, t.attemptTimeout)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+
+	attemptReq := req.Clone(ctx)
+	if attempt > 1 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, nil, err
+		}
+		attemptReq.Body = body
+	}
+
+	return attemptReq, cancel, nil
+}
+
+// backoff returns the delay before the next attempt using the "full jitter" strategy,
+// i.e. a random delay between 0 and the exponential backoff.
+func (t *Transport) backoff(attempt int) time.Duration {
+	delay := t.baseDelay << (attempt - 1)
+	if delay <= 0 || delay > t.maxDelay {
+		delay = t.maxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return rand.N(delay + 1)
+}
+
+// isIdempotent reports whether sending req several times has the same effect
+// as sending it once.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "",
+		http.MethodGet,
+		http.MethodHead,
+		http.MethodOptions,
+		http.MethodPut,
+		http.MethodDelete:
+		return true
+	default:
+		return req.Header.Get("Idempotency-Key") != ""
+	}
+}
+
+func isReplayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		// The caller gave up on the request, retrying is pointless.
+		return req.Context().Err() == nil
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter returns the delay requested by the server in the Retry-After header
+// which can either be a number of seconds or an HTTP date.
+func parseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+func statusCode(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.CopyN(io.Discard, body, maxDrainSize)
+	body.Close()
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// cancelOnCloseBody releases the context of an attempt once the body of its response is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
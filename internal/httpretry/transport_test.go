@@ -0,0 +1,206 @@
+package httpretry_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/matthieugusmini/rift/internal/httpretry"
+)
+
+func TestTransport(t *testing.T) {
+	t.Run("retries server errors until success", func(t *testing.T) {
+		srv, calls := newFlakyServer(t, 2, http.StatusServiceUnavailable, nil)
+		client := newTestClient()
+
+		resp, err := client.Get(srv.URL)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "ok", readBody(t, resp))
+		require.EqualValues(t, 3, calls.Load())
+	})
+
+	t.Run("returns last response when attempts are exhausted", func(t *testing.T) {
+		srv, calls := newFlakyServer(t, 10, http.StatusBadGateway, nil)
+		client := newTestClient(httpretry.WithMaxAttempts(3))
+
+		resp, err := client.Get(srv.URL)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		require.EqualValues(t, 3, calls.Load())
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		srv, calls := newFlakyServer(t, 10, http.StatusNotFound, nil)
+		client := newTestClient()
+
+		resp, err := client.Get(srv.URL)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+		require.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("honors Retry-After", func(t *testing.T) {
+		header := http.Header{"Retry-After": []string{"1"}}
+		srv, calls := newFlakyServer(t, 1, http.StatusTooManyRequests, header)
+		client := newTestClient()
+
+		start := time.Now()
+		resp, err := client.Get(srv.URL)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.EqualValues(t, 2, calls.Load())
+		require.GreaterOrEqual(t, time.Since(start), time.Second)
+	})
+
+	t.Run("gives up when Retry-After exceeds max elapsed time", func(t *testing.T) {
+		header := http.Header{"Retry-After": []string{"60"}}
+		srv, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable, header)
+		client := newTestClient(httpretry.WithMaxElapsedTime(time.Second))
+
+		resp, err := client.Get(srv.URL)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		require.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("retries attempts which timed out", func(t *testing.T) {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				<-r.Context().Done()
+				return
+			}
+			io.WriteString(w, "ok")
+		}))
+		t.Cleanup(srv.Close)
+		client := newTestClient(httpretry.WithAttemptTimeout(50 * time.Millisecond))
+
+		resp, err := client.Get(srv.URL)
+
+		require.NoError(t, err)
+		require.Equal(t, "ok", readBody(t, resp))
+		require.EqualValues(t, 2, calls.Load())
+	})
+
+	t.Run("replays the request body", func(t *testing.T) {
+		var (
+			calls  atomic.Int32
+			bodies []string
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}))
+		t.Cleanup(srv.Close)
+		client := newTestClient()
+
+		req, err := http.NewRequest(http.MethodPut, srv.URL, strings.NewReader("faker"))
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, []string{"faker", "faker"}, bodies)
+	})
+
+	t.Run("does not retry non-idempotent requests", func(t *testing.T) {
+		srv, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable, nil)
+		client := newTestClient()
+
+		resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("faker"))
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		require.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("retries requests with an idempotency key", func(t *testing.T) {
+		srv, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable, nil)
+		client := newTestClient()
+
+		req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("faker"))
+		require.NoError(t, err)
+		req.Header.Set("Idempotency-Key", "tralalero-tralala")
+		resp, err := client.Do(req)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.EqualValues(t, 2, calls.Load())
+	})
+
+	t.Run("cancels the attempt in flight when max elapsed time is exceeded", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		t.Cleanup(srv.Close)
+		client := newTestClient(httpretry.WithMaxElapsedTime(100 * time.Millisecond))
+
+		start := time.Now()
+		_, err := client.Get(srv.URL)
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), time.Second)
+	})
+}
+
+// newFlakyServer returns a server responding failures times with the given status code
+// and headers before responding "ok".
+func newFlakyServer(
+	t *testing.T,
+	failures int32,
+	statusCode int,
+	header http.Header,
+) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(statusCode)
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &calls
+}
+
+func newTestClient(opts ...httpretry.Option) *http.Client {
+	opts = append(
+		[]httpretry.Option{httpretry.WithBackoff(time.Millisecond, 10*time.Millisecond)},
+		opts...,
+	)
+	return &http.Client{
+		Transport: httpretry.NewTransport(nil, slog.New(slog.DiscardHandler), opts...),
+	}
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(b)
+}
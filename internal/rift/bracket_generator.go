@@ -0,0 +1,347 @@
+package rift
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/matthieugusmini/go-lolesports"
+)
+
+// Dimensions of the bracket as rendered by the UI, in rows.
+const (
+	// Rows taken by the title of a round and the empty line below it.
+	generatedTitleHeight = 2
+	// Rows taken by a match and the empty line below it.
+	generatedMatchHeight = 6
+	// Rows between the last match of the upper bracket and the lower bracket.
+	generatedLowerBracketGap = 2
+)
+
+const lowerBracketLabel = "Lower bracket"
+
+// generatedMatch holds what is known about a match of the stage to lay it out.
+type generatedMatch struct {
+	round int
+	lower bool
+	// Indexes of the matches of the stage whose winner or loser plays this match.
+	previous []int
+	// Row of the top of the match.
+	top int
+}
+
+func (m generatedMatch) center() int { return m.top + 2 }
+
+// GenerateBracketTemplate derives a bracket template from the matches of a bracket stage,
+// for the stages which have no curated template.
+//
+// Rounds are inferred from the previous matches of each match, or by halving
+// the number of matches of each round when they are unknown (single elimination).
+// Matches played by the loser of a previous match are laid out below the others
+// (lower bracket of a double elimination).
+//
+// The matches must be listed round by round, as returned by the LoL Esports API,
+// since the template displays them in that order.
+func GenerateBracketTemplate(matches []lolesports.Match) BracketTemplate {
+	if len(matches) == 0 {
+		return BracketTemplate{}
+	}
+
+	generated := make([]generatedMatch, len(matches))
+	if !linkPreviousMatches(matches, generated) {
+		splitRoundsInHalves(generated)
+	}
+	isDoubleElimination := classifyLowerBracket(generated)
+	layOutMatches(generated)
+
+	roundCount := generated[len(generated)-1].round + 1
+	tmpl := BracketTemplate{Rounds: make([]Round, roundCount)}
+	for i := range tmpl.Rounds {
+		tmpl.Rounds[i].Title = generatedRoundTitle(generated, i, roundCount, isDoubleElimination)
+	}
+
+	prevBottom := make([]int, roundCount)
+	hasLowerLabel := make([]bool, roundCount)
+	for _, m := range generated {
+		round := &tmpl.Rounds[m.round]
+
+		above := m.top - generatedTitleHeight
+		if len(round.Matches) > 0 {
+			above = m.top - prevBottom[m.round]
+		}
+		prevBottom[m.round] = m.top + generatedMatchHeight
+
+		match := Match{DisplayType: DisplayTypeMatch, Above: above}
+		if m.lower && !hasLowerLabel[m.round] {
+			match.Label = lowerBracketLabel
+			hasLowerLabel[m.round] = true
+		}
+		round.Matches = append(round.Matches, match)
+	}
+
+	for i := 1; i < roundCount; i++ {
+		tmpl.Rounds[i].Links = generateLinks(generated, i)
+	}
+
+	return tmpl
+}
+
+// linkPreviousMatches fills the previous matches and the round of each match
+// from the previous match ids returned by the API.
+//
+// A new round starts at the first match playing against the winner or loser
+// of a match of the current round.
+//
+// It returns false if no match references a previous match of the stage.
+func linkPreviousMatches(matches []lolesports.Match, generated []generatedMatch) bool {
+	indexByID := make(map[string]int, len(matches))
+	for i, match := range matches {
+		indexByID[match.ID] = i
+	}
+
+	var (
+		hasLinks   bool
+		round      int
+		roundStart int
+	)
+	for i, match := range matches {
+		for _, id := range match.PreviousMatchIDs {
+			// Matches listed afterwards cannot be displayed in a previous round.
+			if j, ok := indexByID[id]; ok && j < i {
+				generated[i].previous = append(generated[i].previous, j)
+			}
+		}
+		hasLinks = hasLinks || len(generated[i].previous) > 0
+
+		if slices.ContainsFunc(generated[i].previous, func(j int) bool { return j >= roundStart }) {
+			round++
+			roundStart = i
+		}
+		generated[i].round = round
+	}
+
+	return hasLinks
+}
+
+// splitRoundsInHalves groups the matches in rounds of half the size of the previous one
+// as in a single elimination bracket, e.g. 4, 2 and 1 for 7 matches.
+func splitRoundsInHalves(generated []generatedMatch) {
+	var (
+		start         int
+		round         int
+		prevStart     int
+		prevRoundSize int
+	)
+	for start < len(generated) {
+		size := (len(generated) - start + 1) / 2
+		for i := range size {
+			m := &generated[start+i]
+			m.round = round
+			if prevRoundSize == 2*size {
+				m.previous = []int{prevStart + 2*i, prevStart + 2*i + 1}
+			}
+		}
+
+		prevStart, prevRoundSize = start, size
+		start += size
+		round++
+	}
+}
+
+// classifyLowerBracket marks the matches of the lower bracket, i.e. the matches played
+// by the loser of a previous match or by the winner of a lower bracket match.
+//
+// The winner of a match is assumed to play the first of its next matches
+// and the loser the second one. The last match is never part of the lower bracket
+// since it is the grand final.
+//
+// It returns true if the bracket has a lower bracket.
+func classifyLowerBracket(generated []generatedMatch) bool {
+	next := make([][]int, len(generated))
+	for i, m := range generated {
+		for _, j := range m.previous {
+			next[j] = append(next[j], i)
+		}
+	}
+
+	var hasLowerBracket bool
+	for i := range generated[:len(generated)-1] {
+		m := &generated[i]
+		for _, j := range m.previous {
+			if generated[j].lower || (len(next[j]) > 1 && next[j][0] != i) {
+				m.lower = true
+				hasLowerBracket = true
+			}
+		}
+	}
+
+	return hasLowerBracket
+}
+
+// layOutMatches sets the row of each match so that a match is centered
+// between its previous matches of the same bracket, below the other matches
+// of its round. The lower bracket starts below the lowest match of the upper bracket.
+func layOutMatches(generated []generatedMatch) {
+	// Lay out the upper bracket alone first to know where the lower bracket starts.
+	lowerStart := generatedTitleHeight
+	upper := slices.Clone(generated)
+	nextTop := map[int]int{}
+	for i := range upper {
+		if upper[i].lower {
+			continue
+		}
+		placeMatch(upper, i, nextTop, generatedTitleHeight)
+		lowerStart = max(lowerStart, upper[i].top+generatedMatchHeight+generatedLowerBracketGap)
+	}
+
+	clear(nextTop)
+	for i := range generated {
+		minTop := generatedTitleHeight
+		if generated[i].lower {
+			minTop = lowerStart
+		}
+		placeMatch(generated, i, nextTop, minTop)
+	}
+}
+
+// placeMatch sets the row of the i-th match, centered between its previous matches
+// of the same bracket and at least at minTop, then updates the next free row of its round.
+func placeMatch(generated []generatedMatch, i int, nextTop map[int]int, minTop int) {
+	m := &generated[i]
+
+	top := max(minTop, nextTop[m.round])
+	if centers := previousCenters(generated, i); len(centers) > 0 {
+		var sum int
+		for _, c := range centers {
+			sum += c
+		}
+		top = max(top, sum/len(centers)-2)
+	}
+
+	m.top = top
+	nextTop[m.round] = top + generatedMatchHeight
+}
+
+// previousCenters returns the center rows of the previous matches of the i-th match
+// which belong to the same bracket and the previous round, sorted from top to bottom.
+// The grand final, i.e. the last match, is joined by both brackets.
+func previousCenters(generated []generatedMatch, i int) []int {
+	m := generated[i]
+	isGrandFinal := i == len(generated)-1
+
+	var centers []int
+	for _, j := range m.previous {
+		prev := generated[j]
+		if prev.round == m.round-1 && (prev.lower == m.lower || isGrandFinal) {
+			centers = append(centers, prev.center())
+		}
+	}
+	slices.Sort(centers)
+
+	return centers
+}
+
+// generatedLink is a link spanning the rows from top to bottom inclusive.
+type generatedLink struct {
+	Link
+	top, bottom int
+}
+
+// generateLinks returns the links from the matches of the previous round to the matches
+// of the given round, from top to bottom.
+//
+// The winners of the previous matches above and below a match join it with Z-shaped links
+// and a previous match at the same row joins it with a horizontal link.
+// Links which would overlap are not drawn.
+func generateLinks(generated []generatedMatch, round int) []Link {
+	var candidates []generatedLink
+	for i, m := range generated {
+		if m.round != round {
+			continue
+		}
+
+		for _, c := range previousCenters(generated, i) {
+			switch {
+			case c == m.center():
+				candidates = append(candidates, generatedLink{
+					Link: Link{Type: LinkTypeHorizontal},
+					top:  c, bottom: c,
+				})
+			// Joins the upper team of the match.
+			case c <= m.top:
+				candidates = append(candidates, generatedLink{
+					Link: Link{Type: LinkTypeZDown, Height: m.top - c},
+					top:  c, bottom: m.top + 1,
+				})
+			// Joins the lower team of the match.
+			case c >= m.top+4:
+				candidates = append(candidates, generatedLink{
+					Link: Link{Type: LinkTypeZUp, Height: c - m.top - 4},
+					top:  m.top + 3, bottom: c,
+				})
+			}
+		}
+	}
+	slices.SortFunc(candidates, func(a, b generatedLink) int { return cmp.Compare(a.top, b.top) })
+
+	var (
+		links []Link
+		// Row of the cursor once the last link is drawn.
+		cursor int
+		// Whether the last link ends with a newline, otherwise the cursor
+		// is still on its last row.
+		terminated = true
+	)
+	for _, l := range candidates {
+		minTop := cursor
+		if !terminated {
+			minTop++
+		}
+		if l.top < minTop {
+			continue
+		}
+
+		l.Above = l.top - cursor
+		links = append(links, l.Link)
+
+		// Only Z-down links end with a newline, see the UI rendering of the links.
+		terminated = l.Type == LinkTypeZDown
+		cursor = l.bottom
+		if terminated {
+			cursor++
+		}
+	}
+
+	return links
+}
+
+func generatedRoundTitle(
+	generated []generatedMatch,
+	round, roundCount int,
+	isDoubleElimination bool,
+) string {
+	if round == roundCount-1 {
+		if isDoubleElimination {
+			return "Grand Final"
+		}
+		return "Final"
+	}
+
+	if !isDoubleElimination {
+		var size int
+		for _, m := range generated {
+			if m.round == round {
+				size++
+			}
+		}
+
+		switch size {
+		case 2:
+			return "Semifinals"
+		case 4:
+			return "Quarterfinals"
+		}
+	}
+
+	return fmt.Sprintf("Round %d", round+1)
+}
@@ -0,0 +1,99 @@
+package rift_test
+
+import (
+	"testing"
+
+	"github.com/matthieugusmini/go-lolesports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matthieugusmini/rift/internal/rift"
+)
+
+func TestGenerateBracketTemplate(t *testing.T) {
+	t.Run("links matches to their previous matches", func(t *testing.T) {
+		matches := []lolesports.Match{
+			{ID: "1"},
+			{ID: "2"},
+			{ID: "3", PreviousMatchIDs: []string{"1", "2"}},
+		}
+		want := rift.BracketTemplate{
+			Rounds: []rift.Round{
+				{
+					Title: "Semifinals",
+					Matches: []rift.Match{
+						{DisplayType: rift.DisplayTypeMatch},
+						{DisplayType: rift.DisplayTypeMatch},
+					},
+				},
+				{
+					Title: "Final",
+					Links: []rift.Link{
+						{Type: rift.LinkTypeZDown, Height: 1, Above: 4},
+						{Type: rift.LinkTypeZUp, Height: 1, Above: 1},
+					},
+					Matches: []rift.Match{
+						{DisplayType: rift.DisplayTypeMatch, Above: 3},
+					},
+				},
+			},
+		}
+
+		got := rift.GenerateBracketTemplate(matches)
+
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("splits single elimination in halves without previous matches", func(t *testing.T) {
+		matches := testStageMatches(7)
+
+		got := rift.GenerateBracketTemplate(matches)
+
+		require.NoError(t, got.Validate(len(matches)))
+		assert.Equal(t, []string{"Quarterfinals", "Semifinals", "Final"}, roundTitles(got))
+		assert.Len(t, got.Rounds[1].Links, 4)
+		assert.Len(t, got.Rounds[2].Links, 2)
+	})
+
+	t.Run("lays out lower bracket below upper bracket", func(t *testing.T) {
+		matches := []lolesports.Match{
+			{ID: "upper-1"},
+			{ID: "upper-2"},
+			{ID: "upper-final", PreviousMatchIDs: []string{"upper-1", "upper-2"}},
+			{ID: "lower-1", PreviousMatchIDs: []string{"upper-1", "upper-2"}},
+			{ID: "lower-final", PreviousMatchIDs: []string{"lower-1", "upper-final"}},
+			{ID: "grand-final", PreviousMatchIDs: []string{"upper-final", "lower-final"}},
+		}
+
+		got := rift.GenerateBracketTemplate(matches)
+
+		require.NoError(t, got.Validate(len(matches)))
+		assert.Equal(
+			t,
+			[]string{"Round 1", "Round 2", "Round 3", "Grand Final"},
+			roundTitles(got),
+		)
+		assert.Equal(t, "", got.Rounds[1].Matches[0].Label)
+		assert.Equal(t, "Lower bracket", got.Rounds[1].Matches[1].Label)
+		assert.Equal(t, "Lower bracket", got.Rounds[2].Matches[0].Label)
+		assert.Equal(t, "", got.Rounds[3].Matches[0].Label)
+	})
+
+	t.Run("returns valid template for any number of matches", func(t *testing.T) {
+		for n := 1; n <= 32; n++ {
+			matches := testStageMatches(n)
+
+			got := rift.GenerateBracketTemplate(matches)
+
+			assert.NoError(t, got.Validate(n), "%d matches", n)
+		}
+	})
+}
+
+func roundTitles(tmpl rift.BracketTemplate) []string {
+	titles := make([]string, len(tmpl.Rounds))
+	for i, round := range tmpl.Rounds {
+		titles[i] = round.Title
+	}
+	return titles
+}
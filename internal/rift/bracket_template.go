@@ -1,12 +1,93 @@
 // Package rift contains all the services and domain objects for the Rift app.
 package rift
 
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrInvalidBracketTemplate is returned when a bracket template cannot be used
+// to display the bracket of a stage.
+var ErrInvalidBracketTemplate = errors.New("invalid bracket template")
+
 // BracketTemplate represents a template to display a bracket layout.
 //
 // This template structure is inspired by the LoL Fandom bracket templates.
 // See: https://lol.fandom.com/wiki/Template:Bracket
 type BracketTemplate struct {
 	Rounds []Round `json:"rounds,omitempty"`
+	// Generated reports whether the template has been generated from the matches
+	// of the stage because no curated template is associated with it.
+	Generated bool `json:"-"`
+}
+
+// MatchSlots returns the number of matches the template can display.
+func (t BracketTemplate) MatchSlots() int {
+	var slots int
+	for _, round := range t.Rounds {
+		for _, match := range round.Matches {
+			if match.DisplayType == DisplayTypeMatch {
+				slots++
+			}
+		}
+	}
+	return slots
+}
+
+// Validate checks that the template can be used to display a bracket of matchCount matches,
+// i.e. that it has exactly one match slot per match and that its links and matches are
+// well-formed.
+//
+// The returned error wraps [ErrInvalidBracketTemplate] and lists all the problems found.
+func (t BracketTemplate) Validate(matchCount int) error {
+	var problems []error
+	addProblem := func(format string, args ...any) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	if len(t.Rounds) == 0 {
+		addProblem("no rounds")
+	}
+
+	for i, round := range t.Rounds {
+		roundName := fmt.Sprintf("round %d (%q)", i+1, round.Title)
+
+		for j, link := range round.Links {
+			if !slices.Contains(linkTypes, link.Type) {
+				addProblem("%s: link %d: unknown type %q", roundName, j+1, link.Type)
+			}
+			if link.Height < 0 {
+				addProblem("%s: link %d: negative height %d", roundName, j+1, link.Height)
+			}
+			if link.Above < 0 {
+				addProblem("%s: link %d: negative above %d", roundName, j+1, link.Above)
+			}
+		}
+
+		for j, match := range round.Matches {
+			if !slices.Contains(displayTypes, match.DisplayType) {
+				addProblem(
+					"%s: match %d: unknown display type %q",
+					roundName,
+					j+1,
+					match.DisplayType,
+				)
+			}
+			if match.Above < 0 {
+				addProblem("%s: match %d: negative above %d", roundName, j+1, match.Above)
+			}
+		}
+	}
+
+	if slots := t.MatchSlots(); slots != matchCount {
+		addProblem("%d match slots for %d matches", slots, matchCount)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidBracketTemplate, errors.Join(problems...))
+	}
+	return nil
 }
 
 // Round represents a single round in the bracket.
@@ -44,10 +125,21 @@ const (
 	// LinkTypeReseed represents a reseed link.
 	LinkTypeReseed LinkType = "reseed"
 
-	// LinkTypeLoserAdvance links two matches where the looser goes to the next round.
+	// LinkTypeLoserAdvance links two matches where the looser goes to the next round,
+	// e.g. a match of the upper bracket to one of the lower bracket below it.
 	LinkTypeLoserAdvance LinkType = "loser-advance"
 )
 
+var linkTypes = []LinkType{
+	LinkTypeHorizontal,
+	LinkTypeZDown,
+	LinkTypeZUp,
+	LinkTypeLDown,
+	LinkTypeLUp,
+	LinkTypeReseed,
+	LinkTypeLoserAdvance,
+}
+
 // Link represents a link between two rounds of the bracket.
 type Link struct {
 	// Type of the link.
@@ -55,7 +147,7 @@ type Link struct {
 
 	// Height of the link.
 	//
-	// Applies only to the types: z-down, z-up, l-down, l-up.
+	// Applies only to the types: z-down, z-up, l-down, l-up, loser-advance.
 	// It represents the number of vertical lines to add to the link.
 	Height int `json:"height,omitempty"`
 
@@ -74,6 +166,8 @@ const (
 	DisplayTypeHorizontalLine DisplayType = "horizontal-line"
 )
 
+var displayTypes = []DisplayType{DisplayTypeMatch, DisplayTypeHorizontalLine}
+
 // Match represents a match in the bracket.
 type Match struct {
 	// DisplayType is the type of display for the match.
@@ -0,0 +1,71 @@
+package rift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ChainedBracketTemplateClient is a [BracketTemplateClient] retrieving bracket templates
+// from several clients, in order of priority.
+//
+// It is used to override the bracket templates of a remote source with local ones.
+type ChainedBracketTemplateClient struct {
+	clients []BracketTemplateClient
+}
+
+// NewChainedBracketTemplateClient creates a new instance of [ChainedBracketTemplateClient]
+// which tries the given clients in order.
+func NewChainedBracketTemplateClient(
+	clients ...BracketTemplateClient,
+) *ChainedBracketTemplateClient {
+	return &ChainedBracketTemplateClient{clients: clients}
+}
+
+// GetTemplateByStageID returns the bracket template associated with the given stage id
+// by the first client supporting it.
+//
+// An error is returned if no client supports the stage id or if the first client
+// supporting it fails, the next clients are not tried so that a broken override
+// is not silently ignored.
+func (c *ChainedBracketTemplateClient) GetTemplateByStageID(
+	ctx context.Context,
+	stageID string,
+) (BracketTemplate, error) {
+	for _, client := range c.clients {
+		tmpl, err := client.GetTemplateByStageID(ctx, stageID)
+		if errors.Is(err, ErrStageNotSupported) {
+			continue
+		}
+		return tmpl, err
+	}
+
+	return BracketTemplate{}, fmt.Errorf("%w: %q", ErrStageNotSupported, stageID)
+}
+
+// ListAvailableStageIDs returns the ids of the stages supported by any of the clients.
+//
+// An error is returned if any of the clients fails, rather than an incomplete list.
+func (c *ChainedBracketTemplateClient) ListAvailableStageIDs(
+	ctx context.Context,
+) ([]string, error) {
+	var (
+		stageIDs []string
+		seen     = map[string]bool{}
+	)
+	for _, client := range c.clients {
+		ids, err := client.ListAvailableStageIDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				stageIDs = append(stageIDs, id)
+			}
+		}
+	}
+
+	return stageIDs, nil
+}
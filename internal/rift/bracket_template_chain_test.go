@@ -0,0 +1,80 @@
+package rift_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matthieugusmini/rift/internal/rift"
+)
+
+func TestChainedBracketTemplateClient_GetTemplateByStageID(t *testing.T) {
+	override := rift.BracketTemplate{Rounds: []rift.Round{{Title: "override"}}}
+
+	t.Run("returns template of first client supporting the stage", func(t *testing.T) {
+		local := newStubBracketTemplateAPIClient()
+		local.template = override
+		client := rift.NewChainedBracketTemplateClient(local, newStubBracketTemplateAPIClient())
+
+		got, err := client.GetTemplateByStageID(t.Context(), "1")
+
+		require.NoError(t, err)
+		assert.Equal(t, override, got)
+	})
+
+	t.Run("falls back to next client if stage not supported", func(t *testing.T) {
+		local := &stubBracketTemplateAPIClient{
+			err: fmt.Errorf("%w: %q", rift.ErrStageNotSupported, "1"),
+		}
+		client := rift.NewChainedBracketTemplateClient(local, newStubBracketTemplateAPIClient())
+
+		got, err := client.GetTemplateByStageID(t.Context(), "1")
+
+		require.NoError(t, err)
+		assert.Equal(t, testBracketTemplate, got)
+	})
+
+	t.Run("returns error of first client if it fails", func(t *testing.T) {
+		client := rift.NewChainedBracketTemplateClient(
+			newNotFoundBracketTemplateAPIClient(),
+			newStubBracketTemplateAPIClient(),
+		)
+
+		_, err := client.GetTemplateByStageID(t.Context(), "1")
+
+		assert.ErrorIs(t, err, errAPINotFound)
+	})
+
+	t.Run("returns error if no client supports the stage", func(t *testing.T) {
+		client := rift.NewChainedBracketTemplateClient()
+
+		_, err := client.GetTemplateByStageID(t.Context(), "1")
+
+		assert.ErrorIs(t, err, rift.ErrStageNotSupported)
+	})
+}
+
+func TestChainedBracketTemplateClient_ListAvailableStageIDs(t *testing.T) {
+	t.Run("returns stage ids of all clients", func(t *testing.T) {
+		local := &stubBracketTemplateAPIClient{availableStageIDs: []string{"2", "3"}}
+		client := rift.NewChainedBracketTemplateClient(local, newStubBracketTemplateAPIClient())
+
+		got, err := client.ListAvailableStageIDs(t.Context())
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2", "3", "1"}, got)
+	})
+
+	t.Run("returns error if any client fails", func(t *testing.T) {
+		client := rift.NewChainedBracketTemplateClient(
+			newStubBracketTemplateAPIClient(),
+			newNotFoundBracketTemplateAPIClient(),
+		)
+
+		_, err := client.ListAvailableStageIDs(t.Context())
+
+		assert.Error(t, err)
+	})
+}
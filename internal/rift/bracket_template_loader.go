@@ -2,14 +2,29 @@ package rift
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"time"
+
+	"github.com/matthieugusmini/go-lolesports"
+	"golang.org/x/sync/singleflight"
 )
 
+const availableStageIDsKey = "available_stage_ids"
+
+// ErrStageNotSupported is returned by a [BracketTemplateClient] when no bracket template
+// is associated with a stage.
+var ErrStageNotSupported = errors.New("stage ID is not supported")
+
 // BracketTemplateClient represents a client to retrieve bracket templates
 // performing I/O (e.g. network).
 type BracketTemplateClient interface {
 	// GetTemplateByStageID shoulds return the BracketTemplate
 	// associated to the given stage id.
+	//
+	// An error wrapping ErrStageNotSupported should be returned
+	// if no bracket template is associated with the stage id.
 	GetTemplateByStageID(ctx context.Context, stageID string) (BracketTemplate, error)
 
 	// ListAvailableStageIDs returns the list of ids of all stages
@@ -19,46 +34,164 @@ type BracketTemplateClient interface {
 
 // BracketTemplateLoader handles loading bracket templates from multiple sources.
 type BracketTemplateLoader struct {
-	client BracketTemplateClient
-	cache  Cache[BracketTemplate]
+	client        BracketTemplateClient
+	cache         Cache[BracketTemplate]
+	stageIDsCache Cache[[]string]
+	fallback      BracketTemplateClient
+	staleness     staleness
+	// Deduplicates the identical loads in flight.
+	group  singleflight.Group
 	logger *slog.Logger
 }
 
+// BracketTemplateLoaderOption represents a functional option to customize
+// a [BracketTemplateLoader].
+type BracketTemplateLoaderOption func(*BracketTemplateLoader)
+
+// WithFallbackClient sets a client used as a last resort when the bracket templates
+// can be loaded neither from the client nor from the cache, even expired.
+//
+// The data it returns is never stored in the cache.
+func WithFallbackClient(client BracketTemplateClient) BracketTemplateLoaderOption {
+	return func(l *BracketTemplateLoader) {
+		l.fallback = client
+	}
+}
+
 // NewBracketTemplateLoader creates a new instance of BracketTemplateLoader.
 func NewBracketTemplateLoader(
 	bracketTemplateClient BracketTemplateClient,
 	cache Cache[BracketTemplate],
+	stageIDsCache Cache[[]string],
 	logger *slog.Logger,
+	opts ...BracketTemplateLoaderOption,
 ) *BracketTemplateLoader {
-	return &BracketTemplateLoader{
-		client: bracketTemplateClient,
-		cache:  cache,
-		logger: logger.WithGroup("bracketTemplateLoader"),
+	l := &BracketTemplateLoader{
+		client:        bracketTemplateClient,
+		cache:         cache,
+		stageIDsCache: stageIDsCache,
+		logger:        logger.WithGroup("bracketTemplateLoader"),
 	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
 }
 
-// ListAvailableStageIDs returns the list of available stage ids in the server.
+// ListAvailableStageIDs tries to load the list of ids of the stages which have
+// a bracket template from the underlying cache first and if not found fetches it
+// using the client. If the client fails, the expired list from the cache is returned instead,
+// or the one of the fallback client if any. Concurrent calls share a single call to the client.
 //
-// An error is returned if it cannot fetch the data.
+// An error is returned only if the list cannot be loaded from any source.
+// Errors returned by the cache are not forwarded and are just logged instead.
 func (l *BracketTemplateLoader) ListAvailableStageIDs(ctx context.Context) ([]string, error) {
-	stageIDs, err := l.client.ListAvailableStageIDs(ctx)
+	return coalesce(
+		ctx,
+		&l.group,
+		availableStageIDsKey,
+		func(ctx context.Context) ([]string, error) {
+			return l.listAvailableStageIDs(ctx)
+		},
+	)
+}
+
+func (l *BracketTemplateLoader) listAvailableStageIDs(ctx context.Context) ([]string, error) {
+	stageIDs, ok, err := l.stageIDsCache.Get(availableStageIDsKey)
 	if err != nil {
+		l.logger.Debug(
+			"Available stage ids not present in cache",
+			slog.Any("err", err),
+		)
+	}
+	if ok {
+		return stageIDs, nil
+	}
+
+	stageIDs, err = l.client.ListAvailableStageIDs(ctx)
+	if err != nil {
+		stageIDs, ok := getStale(l.stageIDsCache, availableStageIDsKey, &l.staleness, l.logger)
+		if ok {
+			return stageIDs, nil
+		}
+		if stageIDs, ok := l.listFallbackStageIDs(ctx); ok {
+			return stageIDs, nil
+		}
 		return nil, err
 	}
+	l.staleness.markFresh()
+
+	if err := l.stageIDsCache.Set(availableStageIDsKey, stageIDs); err != nil {
+		l.logger.Warn(
+			"Failed to cache available stage ids",
+			slog.Any("err", err),
+		)
+	}
+
 	return stageIDs, nil
 }
 
 // Load tries to load the bracket template associated to the given stage ID
 // from the underlying cache first and if not found fetches it using the client.
+// If the client fails, the expired template from the cache is returned instead,
+// or the one of the fallback client if any.
+// Concurrent loads of the same template share a single call to the client.
+//
+// If no template is associated with the stage, or if it cannot be loaded from any source
+// (e.g. offline without cached template), one is generated from the matches of the stage
+// instead, see [GenerateBracketTemplate], and its Generated field is set.
 //
-// An error is returned only if the client cannot load the template.
+// The template is validated against the matches of the stage so that
+// it can be displayed safely, an error wrapping [ErrInvalidBracketTemplate] is returned
+// otherwise.
+//
+// An error is returned if the template cannot be loaded from any source.
 // Errors returned by the cache are not forwarded and are just logged instead.
 func (l *BracketTemplateLoader) Load(
 	ctx context.Context,
 	stageID string,
+	matches []lolesports.Match,
 ) (BracketTemplate, error) {
-	var tmpl BracketTemplate
+	tmpl, err := coalesce(
+		ctx,
+		&l.group,
+		"template:"+stageID,
+		func(ctx context.Context) (BracketTemplate, error) {
+			return l.load(ctx, stageID)
+		},
+	)
+	switch {
+	// Nobody is waiting for the template anymore.
+	case ctx.Err() != nil:
+		return BracketTemplate{}, ctx.Err()
+	case errors.Is(err, ErrStageNotSupported):
+		l.logger.Info("Generating bracket template", slog.String("stageId", stageID))
+		tmpl = l.generate(matches)
+	case err != nil:
+		l.logger.Warn(
+			"Failed to load bracket template, generating one instead",
+			slog.Any("err", err),
+			slog.String("stageId", stageID),
+		)
+		tmpl = l.generate(matches)
+	}
+
+	if err := tmpl.Validate(len(matches)); err != nil {
+		return BracketTemplate{}, fmt.Errorf("bracket template of stage %q: %w", stageID, err)
+	}
+
+	return tmpl, nil
+}
+
+func (l *BracketTemplateLoader) generate(matches []lolesports.Match) BracketTemplate {
+	tmpl := GenerateBracketTemplate(matches)
+	tmpl.Generated = true
+	return tmpl
+}
 
+func (l *BracketTemplateLoader) load(ctx context.Context, stageID string) (BracketTemplate, error) {
 	tmpl, ok, err := l.cache.Get(stageID)
 	if err != nil {
 		l.logger.Debug(
@@ -73,8 +206,15 @@ func (l *BracketTemplateLoader) Load(
 
 	tmpl, err = l.client.GetTemplateByStageID(ctx, stageID)
 	if err != nil {
+		if tmpl, ok := getStale(l.cache, stageID, &l.staleness, l.logger); ok {
+			return tmpl, nil
+		}
+		if tmpl, ok := l.loadFallbackTemplate(ctx, stageID); ok {
+			return tmpl, nil
+		}
 		return BracketTemplate{}, err
 	}
+	l.staleness.markFresh()
 
 	if err := l.cache.Set(stageID, tmpl); err != nil {
 		l.logger.Warn(
@@ -86,3 +226,49 @@ func (l *BracketTemplateLoader) Load(
 
 	return tmpl, nil
 }
+
+func (l *BracketTemplateLoader) loadFallbackTemplate(
+	ctx context.Context,
+	stageID string,
+) (BracketTemplate, bool) {
+	if l.fallback == nil {
+		return BracketTemplate{}, false
+	}
+
+	tmpl, err := l.fallback.GetTemplateByStageID(ctx, stageID)
+	if err != nil {
+		l.logger.Debug(
+			"Bracket template not present in fallback",
+			slog.Any("err", err),
+			slog.String("stageId", stageID),
+		)
+		return BracketTemplate{}, false
+	}
+
+	l.logger.Info("Serving bracket template from fallback", slog.String("stageId", stageID))
+	return tmpl, true
+}
+
+func (l *BracketTemplateLoader) listFallbackStageIDs(ctx context.Context) ([]string, bool) {
+	if l.fallback == nil {
+		return nil, false
+	}
+
+	stageIDs, err := l.fallback.ListAvailableStageIDs(ctx)
+	if err != nil {
+		l.logger.Debug("Available stage ids not present in fallback", slog.Any("err", err))
+		return nil, false
+	}
+
+	l.logger.Info("Serving available stage ids from fallback")
+	return stageIDs, true
+}
+
+// StaleSince returns the time at which the oldest expired template served by the loader,
+// because it could not be fetched, was stored.
+//
+// Additionally a boolean is returned to indicate whether a stale template has been served
+// since the last successful fetch.
+func (l *BracketTemplateLoader) StaleSince() (time.Time, bool) {
+	return l.staleness.get()
+}
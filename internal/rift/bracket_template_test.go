@@ -4,8 +4,13 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/matthieugusmini/go-lolesports"
 	"github.com/matthieugusmini/rift/internal/rift"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -18,9 +23,14 @@ func TestBracketTemplateLoader_Load(t *testing.T) {
 	t.Run("returns cached template", func(t *testing.T) {
 		fakeCache := newFakeCacheWith(map[string]rift.BracketTemplate{stageID: want})
 		stubAPIClient := newStubBracketTemplateAPIClient()
-		loader := rift.NewBracketTemplateLoader(stubAPIClient, fakeCache, slog.Default())
+		loader := rift.NewBracketTemplateLoader(
+			stubAPIClient,
+			fakeCache,
+			newFakeCache[[]string](),
+			slog.Default(),
+		)
 
-		got, err := loader.Load(t.Context(), stageID)
+		got, err := loader.Load(t.Context(), stageID, testStageMatches(1))
 
 		require.NoError(t, err)
 		assert.Equal(t, want, got)
@@ -29,9 +39,14 @@ func TestBracketTemplateLoader_Load(t *testing.T) {
 	t.Run("returns template from API and update cache", func(t *testing.T) {
 		fakeCache := newFakeCache[rift.BracketTemplate]()
 		stubAPIClient := newStubBracketTemplateAPIClient()
-		loader := rift.NewBracketTemplateLoader(stubAPIClient, fakeCache, slog.Default())
+		loader := rift.NewBracketTemplateLoader(
+			stubAPIClient,
+			fakeCache,
+			newFakeCache[[]string](),
+			slog.Default(),
+		)
 
-		got, err := loader.Load(t.Context(), stageID)
+		got, err := loader.Load(t.Context(), stageID, testStageMatches(1))
 
 		require.NoError(t, err)
 		assert.Equal(t, want, got)
@@ -40,23 +55,129 @@ func TestBracketTemplateLoader_Load(t *testing.T) {
 		assert.True(t, ok)
 	})
 
-	t.Run("returns error if not in cache and API not found", func(t *testing.T) {
+	t.Run("returns generated template if not in cache and API fails", func(t *testing.T) {
 		fakeCache := newFakeCache[rift.BracketTemplate]()
 		notFoundAPIClient := newNotFoundBracketTemplateAPIClient()
-		loader := rift.NewBracketTemplateLoader(notFoundAPIClient, fakeCache, slog.Default())
+		loader := rift.NewBracketTemplateLoader(
+			notFoundAPIClient,
+			fakeCache,
+			newFakeCache[[]string](),
+			slog.Default(),
+			rift.WithFallbackClient(notFoundAPIClient),
+		)
+		matches := testStageMatches(3)
 
-		_, err := loader.Load(t.Context(), stageID)
+		got, err := loader.Load(t.Context(), stageID, matches)
 
-		assert.Error(t, err)
+		require.NoError(t, err)
+		assert.Equal(t, rift.GenerateBracketTemplate(matches).Rounds, got.Rounds)
+		assert.True(t, got.Generated)
+		// Assert that the generated template has not been cached
+		assert.Empty(t, fakeCache.entries)
+	})
+
+	t.Run("returns error if caller gives up", func(t *testing.T) {
+		notFoundAPIClient := newNotFoundBracketTemplateAPIClient()
+		loader := rift.NewBracketTemplateLoader(
+			notFoundAPIClient,
+			newFakeCache[rift.BracketTemplate](),
+			newFakeCache[[]string](),
+			slog.Default(),
+		)
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		_, err := loader.Load(ctx, stageID, testStageMatches(1))
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("returns generated template if stage not supported", func(t *testing.T) {
+		fakeCache := newFakeCache[rift.BracketTemplate]()
+		unsupportedAPIClient := &stubBracketTemplateAPIClient{err: rift.ErrStageNotSupported}
+		loader := rift.NewBracketTemplateLoader(
+			unsupportedAPIClient,
+			fakeCache,
+			newFakeCache[[]string](),
+			slog.Default(),
+		)
+		matches := testStageMatches(3)
+
+		got, err := loader.Load(t.Context(), stageID, matches)
+
+		require.NoError(t, err)
+		assert.Equal(t, rift.GenerateBracketTemplate(matches).Rounds, got.Rounds)
+		assert.True(t, got.Generated)
+		// Assert that the generated template has not been cached
+		assert.Empty(t, fakeCache.entries)
+	})
+
+	t.Run("returns stale template if API fails", func(t *testing.T) {
+		fakeCache := newFakeCache[rift.BracketTemplate]()
+		fakeCache.staleEntries = map[string]rift.BracketTemplate{stageID: want}
+		notFoundAPIClient := newNotFoundBracketTemplateAPIClient()
+		loader := rift.NewBracketTemplateLoader(
+			notFoundAPIClient,
+			fakeCache,
+			newFakeCache[[]string](),
+			slog.Default(),
+		)
+
+		got, err := loader.Load(t.Context(), stageID, testStageMatches(1))
+
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		staleSince, ok := loader.StaleSince()
+		assert.True(t, ok)
+		assert.Equal(t, testStaleSince, staleSince)
+	})
+
+	t.Run("returns fallback template if API and cache fail", func(t *testing.T) {
+		fakeCache := newFakeCache[rift.BracketTemplate]()
+		notFoundAPIClient := newNotFoundBracketTemplateAPIClient()
+		loader := rift.NewBracketTemplateLoader(
+			notFoundAPIClient,
+			fakeCache,
+			newFakeCache[[]string](),
+			slog.Default(),
+			rift.WithFallbackClient(newStubBracketTemplateAPIClient()),
+		)
+
+		got, err := loader.Load(t.Context(), stageID, testStageMatches(1))
+
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		// Assert that the fallback template has not been cached
+		assert.Empty(t, fakeCache.entries)
+	})
+
+	t.Run("returns error if template does not match the stage", func(t *testing.T) {
+		fakeCache := newFakeCacheWith(map[string]rift.BracketTemplate{stageID: want})
+		stubAPIClient := newStubBracketTemplateAPIClient()
+		loader := rift.NewBracketTemplateLoader(
+			stubAPIClient,
+			fakeCache,
+			newFakeCache[[]string](),
+			slog.Default(),
+		)
+
+		_, err := loader.Load(t.Context(), stageID, testStageMatches(3))
+
+		assert.ErrorIs(t, err, rift.ErrInvalidBracketTemplate)
 	})
 
 	t.Run("returns template even if fails to get cached value", func(t *testing.T) {
 		fakeCache := newFakeCache[rift.BracketTemplate]()
 		fakeCache.getErr = errCacheGet
 		stubAPIClient := newStubBracketTemplateAPIClient()
-		loader := rift.NewBracketTemplateLoader(stubAPIClient, fakeCache, slog.Default())
+		loader := rift.NewBracketTemplateLoader(
+			stubAPIClient,
+			fakeCache,
+			newFakeCache[[]string](),
+			slog.Default(),
+		)
 
-		got, err := loader.Load(t.Context(), stageID)
+		got, err := loader.Load(t.Context(), stageID, testStageMatches(1))
 
 		require.NoError(t, err)
 		assert.Equal(t, want, got)
@@ -69,22 +190,135 @@ func TestBracketTemplateLoader_Load(t *testing.T) {
 		fakeCache := newFakeCache[rift.BracketTemplate]()
 		fakeCache.setErr = errCacheSet
 		stubAPIClient := newStubBracketTemplateAPIClient()
-		loader := rift.NewBracketTemplateLoader(stubAPIClient, fakeCache, slog.Default())
+		loader := rift.NewBracketTemplateLoader(
+			stubAPIClient,
+			fakeCache,
+			newFakeCache[[]string](),
+			slog.Default(),
+		)
 
-		got, err := loader.Load(t.Context(), stageID)
+		got, err := loader.Load(t.Context(), stageID, testStageMatches(1))
 
 		require.NoError(t, err)
 		assert.Equal(t, want, got)
 	})
 }
 
+func TestBracketTemplate_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		tmpl       rift.BracketTemplate
+		matchCount int
+		wantErr    bool
+	}{
+		{
+			name:       "valid template",
+			tmpl:       testBracketTemplate,
+			matchCount: 1,
+		},
+		{
+			name:       "more match slots than matches",
+			tmpl:       testBracketTemplate,
+			matchCount: 0,
+			wantErr:    true,
+		},
+		{
+			name:       "less match slots than matches",
+			tmpl:       testBracketTemplate,
+			matchCount: 2,
+			wantErr:    true,
+		},
+		{
+			name:    "no rounds",
+			tmpl:    rift.BracketTemplate{},
+			wantErr: true,
+		},
+		{
+			name: "unknown link type",
+			tmpl: rift.BracketTemplate{Rounds: []rift.Round{
+				{Links: []rift.Link{{Type: "diagonal"}}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "negative link height",
+			tmpl: rift.BracketTemplate{Rounds: []rift.Round{
+				{Links: []rift.Link{{Type: rift.LinkTypeZDown, Height: -1}}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "negative link above",
+			tmpl: rift.BracketTemplate{Rounds: []rift.Round{
+				{Links: []rift.Link{{Type: rift.LinkTypeHorizontal, Above: -1}}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "unknown display type",
+			tmpl: rift.BracketTemplate{Rounds: []rift.Round{
+				{Matches: []rift.Match{{DisplayType: "bo7"}}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "negative match above",
+			tmpl: rift.BracketTemplate{Rounds: []rift.Round{
+				{Matches: []rift.Match{{DisplayType: rift.DisplayTypeMatch, Above: -1}}},
+			}},
+			matchCount: 1,
+			wantErr:    true,
+		},
+		{
+			name: "horizontal lines are not match slots",
+			tmpl: rift.BracketTemplate{Rounds: []rift.Round{
+				{Matches: []rift.Match{{DisplayType: rift.DisplayTypeHorizontalLine}}},
+			}},
+			matchCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tmpl.Validate(tt.matchCount)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, rift.ErrInvalidBracketTemplate)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestBracketTemplateLoader_ListAvailableStageIDs(t *testing.T) {
 	want := testAvailableStageIDs
 
 	t.Run("returns stage ids", func(t *testing.T) {
 		fakeCache := newFakeCache[rift.BracketTemplate]()
 		stubAPIClient := newStubBracketTemplateAPIClient()
-		loader := rift.NewBracketTemplateLoader(stubAPIClient, fakeCache, slog.Default())
+		loader := rift.NewBracketTemplateLoader(
+			stubAPIClient,
+			fakeCache,
+			newFakeCache[[]string](),
+			slog.Default(),
+		)
+
+		got, err := loader.ListAvailableStageIDs(t.Context())
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, want, got)
+	})
+
+	t.Run("returns cached stage ids", func(t *testing.T) {
+		stageIDsCache := newFakeCacheWith(map[string][]string{"available_stage_ids": want})
+		notFoundAPIClient := newNotFoundBracketTemplateAPIClient()
+		loader := rift.NewBracketTemplateLoader(
+			notFoundAPIClient,
+			newFakeCache[rift.BracketTemplate](),
+			stageIDsCache,
+			slog.Default(),
+		)
 
 		got, err := loader.ListAvailableStageIDs(t.Context())
 
@@ -92,10 +326,69 @@ func TestBracketTemplateLoader_ListAvailableStageIDs(t *testing.T) {
 		assert.ElementsMatch(t, want, got)
 	})
 
+	t.Run("returns stage ids from API and update cache", func(t *testing.T) {
+		stageIDsCache := newFakeCache[[]string]()
+		stubAPIClient := newStubBracketTemplateAPIClient()
+		loader := rift.NewBracketTemplateLoader(
+			stubAPIClient,
+			newFakeCache[rift.BracketTemplate](),
+			stageIDsCache,
+			slog.Default(),
+		)
+
+		_, err := loader.ListAvailableStageIDs(t.Context())
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, want, stageIDsCache.entries["available_stage_ids"])
+	})
+
+	t.Run("returns stale stage ids if API fails", func(t *testing.T) {
+		stageIDsCache := newFakeCache[[]string]()
+		stageIDsCache.staleEntries = map[string][]string{"available_stage_ids": want}
+		notFoundAPIClient := newNotFoundBracketTemplateAPIClient()
+		loader := rift.NewBracketTemplateLoader(
+			notFoundAPIClient,
+			newFakeCache[rift.BracketTemplate](),
+			stageIDsCache,
+			slog.Default(),
+		)
+
+		got, err := loader.ListAvailableStageIDs(t.Context())
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, want, got)
+		staleSince, ok := loader.StaleSince()
+		assert.True(t, ok)
+		assert.Equal(t, testStaleSince, staleSince)
+	})
+
+	t.Run("returns fallback stage ids if API and cache fail", func(t *testing.T) {
+		stageIDsCache := newFakeCache[[]string]()
+		notFoundAPIClient := newNotFoundBracketTemplateAPIClient()
+		loader := rift.NewBracketTemplateLoader(
+			notFoundAPIClient,
+			newFakeCache[rift.BracketTemplate](),
+			stageIDsCache,
+			slog.Default(),
+			rift.WithFallbackClient(newStubBracketTemplateAPIClient()),
+		)
+
+		got, err := loader.ListAvailableStageIDs(t.Context())
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, want, got)
+		assert.Empty(t, stageIDsCache.entries)
+	})
+
 	t.Run("returns error if cannot fetch", func(t *testing.T) {
 		fakeCache := newFakeCache[rift.BracketTemplate]()
 		stubAPIClient := newNotFoundBracketTemplateAPIClient()
-		loader := rift.NewBracketTemplateLoader(stubAPIClient, fakeCache, slog.Default())
+		loader := rift.NewBracketTemplateLoader(
+			stubAPIClient,
+			fakeCache,
+			newFakeCache[[]string](),
+			slog.Default(),
+		)
 
 		_, err := loader.ListAvailableStageIDs(t.Context())
 
@@ -103,6 +396,73 @@ func TestBracketTemplateLoader_ListAvailableStageIDs(t *testing.T) {
 	})
 }
 
+func TestBracketTemplateLoader_Load_CoalescesConcurrentLoads(t *testing.T) {
+	fakeCache := newFakeCache[rift.BracketTemplate]()
+	stubAPIClient := newStubBracketTemplateAPIClient()
+	stubAPIClient.release = make(chan struct{})
+	loader := rift.NewBracketTemplateLoader(
+		stubAPIClient,
+		fakeCache,
+		newFakeCache[[]string](),
+		slog.Default(),
+	)
+
+	const n = 5
+	var wg sync.WaitGroup
+	results := make([]rift.BracketTemplate, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tmpl, err := loader.Load(t.Context(), "1", testStageMatches(1))
+			assert.NoError(t, err)
+			results[i] = tmpl
+		}()
+	}
+	// Give the time to all the loads to wait for the one in flight.
+	time.Sleep(50 * time.Millisecond)
+	close(stubAPIClient.release)
+	wg.Wait()
+
+	assert.EqualValues(t, 1, stubAPIClient.calls.Load())
+	for _, got := range results {
+		assert.Equal(t, testBracketTemplate, got)
+	}
+}
+
+func TestBracketTemplateLoader_Load_CoalescedLoadOutlivesCanceledCaller(t *testing.T) {
+	stubAPIClient := newStubBracketTemplateAPIClient()
+	stubAPIClient.release = make(chan struct{})
+	loader := rift.NewBracketTemplateLoader(
+		stubAPIClient,
+		newFakeCache[rift.BracketTemplate](),
+		newFakeCache[[]string](),
+		slog.Default(),
+	)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	firstErr := make(chan error)
+	go func() {
+		_, err := loader.Load(ctx, "1", testStageMatches(1))
+		firstErr <- err
+	}()
+	// Give the time to the first load to start the call to the API.
+	time.Sleep(50 * time.Millisecond)
+	second := make(chan rift.BracketTemplate)
+	go func() {
+		tmpl, err := loader.Load(t.Context(), "1", testStageMatches(1))
+		assert.NoError(t, err)
+		second <- tmpl
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-firstErr, context.Canceled)
+	close(stubAPIClient.release)
+
+	assert.Equal(t, testBracketTemplate, <-second)
+	assert.EqualValues(t, 1, stubAPIClient.calls.Load())
+}
+
 var testBracketTemplate = rift.BracketTemplate{
 	Rounds: []rift.Round{
 		{
@@ -117,6 +477,15 @@ var testBracketTemplate = rift.BracketTemplate{
 	},
 }
 
+// testStageMatches returns n matches without previous matches.
+func testStageMatches(n int) []lolesports.Match {
+	matches := make([]lolesports.Match, n)
+	for i := range matches {
+		matches[i].ID = strconv.Itoa(i + 1)
+	}
+	return matches
+}
+
 var testAvailableStageIDs = []string{"1", "2"}
 
 var errAPINotFound = errors.New(
@@ -127,6 +496,9 @@ type stubBracketTemplateAPIClient struct {
 	template          rift.BracketTemplate
 	availableStageIDs []string
 	err               error
+	calls             atomic.Int32
+	// If not nil, the calls block until it is closed.
+	release chan struct{}
 }
 
 func newStubBracketTemplateAPIClient() *stubBracketTemplateAPIClient {
@@ -141,9 +513,17 @@ func newNotFoundBracketTemplateAPIClient() *stubBracketTemplateAPIClient {
 }
 
 func (api *stubBracketTemplateAPIClient) GetTemplateByStageID(
-	_ context.Context,
+	ctx context.Context,
 	_ string,
 ) (rift.BracketTemplate, error) {
+	api.calls.Add(1)
+	if api.release != nil {
+		select {
+		case <-ctx.Done():
+			return rift.BracketTemplate{}, ctx.Err()
+		case <-api.release:
+		}
+	}
 	if api.err != nil {
 		return rift.BracketTemplate{}, api.err
 	}
@@ -1,5 +1,7 @@
 package rift
 
+import "time"
+
 // Cache represents a key/value store with fast read access.
 type Cache[T any] interface {
 	// Get should return:
@@ -8,6 +10,15 @@ type Cache[T any] interface {
 	// - An error(e.g. could not invalidate entry, etc.)
 	Get(key string) (T, bool, error)
 
+	// GetStale should return the same as Get even if the entry has expired,
+	// along with the time at which the entry was stored.
+	GetStale(key string) (T, time.Time, bool, error)
+
 	// Set should create a new entry with key and value in the cache.
 	Set(key string, value T) error
+
+	// SetWithTTL should create a new entry with key and value in the cache
+	// which expires after ttl instead of the default TTL of the cache.
+	// If ttl == 0 the entry should never expire.
+	SetWithTTL(key string, value T, ttl time.Duration) error
 }
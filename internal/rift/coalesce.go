@@ -0,0 +1,42 @@
+package rift
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// coalescedCallTimeout is the maximum duration of a call shared by concurrent callers.
+const coalescedCallTimeout = time.Minute
+
+// coalesce executes fn only once at a time for a given key, concurrent callers
+// with the same key wait for the call in flight and share its result.
+//
+// The shared result must not be modified by the callers.
+// The call in flight is not canceled along with the context of the caller which
+// started it since the other callers may still be waiting for its result, each caller
+// stops waiting as soon as its own context is canceled instead.
+func coalesce[T any](
+	ctx context.Context,
+	group *singleflight.Group,
+	key string,
+	fn func(ctx context.Context) (T, error),
+) (T, error) {
+	ch := group.DoChan(key, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), coalescedCallTimeout)
+		defer cancel()
+
+		return fn(ctx)
+	})
+
+	select {
+	case <-ctx.Done():
+		return *(new(T)), ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return *(new(T)), res.Err
+		}
+		return res.Val.(T), nil
+	}
+}
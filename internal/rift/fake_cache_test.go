@@ -2,6 +2,7 @@ package rift_test
 
 import (
 	"errors"
+	"time"
 )
 
 var (
@@ -11,10 +12,16 @@ var (
 	errCacheSet = errors.New("cache set test error")
 )
 
+var testStaleSince = time.Date(2019, time.November, 10, 0, 0, 0, 0, time.UTC)
+
 type fakeCache[T any] struct {
 	entries map[string]T
-	getErr  error
-	setErr  error
+	// Entries which have expired, they can only be retrieved with GetStale.
+	staleEntries map[string]T
+	// TTLs of the entries set with SetWithTTL.
+	ttls   map[string]time.Duration
+	getErr error
+	setErr error
 }
 
 func newFakeCache[T any]() *fakeCache[T] {
@@ -39,6 +46,20 @@ func (c *fakeCache[T]) Get(key string) (T, bool, error) {
 	return tmpl, true, nil
 }
 
+func (c *fakeCache[T]) GetStale(key string) (T, time.Time, bool, error) {
+	if c.getErr != nil {
+		return *(new(T)), time.Time{}, false, c.getErr
+	}
+
+	if v, ok := c.entries[key]; ok {
+		return v, time.Now(), true, nil
+	}
+	if v, ok := c.staleEntries[key]; ok {
+		return v, testStaleSince, true, nil
+	}
+	return *(new(T)), time.Time{}, false, nil
+}
+
 func (c *fakeCache[T]) Set(key string, value T) error {
 	if c.setErr != nil {
 		return c.setErr
@@ -47,3 +68,15 @@ func (c *fakeCache[T]) Set(key string, value T) error {
 	c.entries[key] = value
 	return nil
 }
+
+func (c *fakeCache[T]) SetWithTTL(key string, value T, ttl time.Duration) error {
+	if err := c.Set(key, value); err != nil {
+		return err
+	}
+
+	if c.ttls == nil {
+		c.ttls = map[string]time.Duration{}
+	}
+	c.ttls[key] = ttl
+	return nil
+}
@@ -0,0 +1,147 @@
+package rift
+
+import (
+	"time"
+
+	"github.com/matthieugusmini/go-lolesports"
+
+	"github.com/matthieugusmini/rift/internal/timeutil"
+)
+
+// FilterMatchEvents returns only the events of type match which have
+// exactly two teams, preserving their order.
+func FilterMatchEvents(events []lolesports.Event) []lolesports.Event {
+	matches := make([]lolesports.Event, 0, len(events))
+
+	for _, event := range events {
+		if event.Type != lolesports.EventTypeMatch {
+			continue
+		}
+
+		// Matches does not always have teams somehow.
+		if len(event.Match.Teams) != 2 {
+			continue
+		}
+
+		matches = append(matches, event)
+	}
+
+	return matches
+}
+
+// ListLeaguesFromTournaments returns the leagues the tournaments belong to
+// without duplicates, in order of appearance.
+func ListLeaguesFromTournaments(tournaments []lolesports.Tournament) []lolesports.League {
+	var (
+		leagues     []lolesports.League
+		seenLeagues = map[string]bool{}
+	)
+	for _, tournament := range tournaments {
+		if _, ok := seenLeagues[tournament.League.ID]; !ok {
+			leagues = append(leagues, tournament.League)
+			seenLeagues[tournament.League.ID] = true
+		}
+	}
+	return leagues
+}
+
+// ListTournamentIDsForLeague returns the ids of the tournaments
+// belonging to the league identified by leagueID.
+func ListTournamentIDsForLeague(tournaments []lolesports.Tournament, leagueID string) []string {
+	var tournamentIDs []string
+	for _, tournament := range tournaments {
+		if tournament.League.ID == leagueID {
+			tournamentIDs = append(tournamentIDs, tournament.ID)
+		}
+	}
+	return tournamentIDs
+}
+
+// ListStagesFromStandings returns the stages of all the standings.
+func ListStagesFromStandings(standings []lolesports.Standings) []lolesports.Stage {
+	var stages []lolesports.Stage
+	for _, standing := range standings {
+		stages = append(stages, standing.Stages...)
+	}
+	return stages
+}
+
+// IsBracketStage returns true if the stage is displayed as a bracket
+// rather than with rankings, false otherwise.
+func IsBracketStage(stage lolesports.Stage) bool {
+	return len(stage.Sections) > 0 && len(stage.Sections[0].Rankings) == 0
+}
+
+// CalculateWinrate returns the percentage of wins, rounded down.
+func CalculateWinrate(wins, losses int) int {
+	totalGames := wins + losses
+	if totalGames == 0 {
+		return 0
+	}
+	return int(float64(wins) / float64(totalGames) * 100)
+}
+
+// TournamentState represents the progress of a tournament or of one of its stages.
+type TournamentState string
+
+// Possible states of a tournament.
+const (
+	TournamentStateUnknown    TournamentState = "UNKNOWN"
+	TournamentStateNotStarted TournamentState = "UPCOMING"
+	TournamentStateInProgress TournamentState = "IN PROGRESS"
+	TournamentStateCompleted  TournamentState = "COMPLETED"
+)
+
+// ComputeTournamentState returns the state of a tournament from its period.
+func ComputeTournamentState(startDate, endDate time.Time) TournamentState {
+	now := time.Now()
+	switch {
+	case now.Before(startDate):
+		return TournamentStateNotStarted
+	case timeutil.IsCurrentTimeBetween(startDate, endDate):
+		return TournamentStateInProgress
+	case now.After(endDate):
+		return TournamentStateCompleted
+	default:
+		return TournamentStateUnknown
+	}
+}
+
+// ComputeStandingsState returns the state of the tournaments of the standings
+// from the outcome of the matches of all their stages.
+//
+// The state is unknown if the standings don't have any match.
+func ComputeStandingsState(standings []lolesports.Standings) TournamentState {
+	var played, unplayed int
+	for _, stage := range ListStagesFromStandings(standings) {
+		for _, section := range stage.Sections {
+			for _, match := range section.Matches {
+				if isMatchPlayed(match) {
+					played++
+				} else {
+					unplayed++
+				}
+			}
+		}
+	}
+
+	switch {
+	case played == 0 && unplayed == 0:
+		return TournamentStateUnknown
+	case unplayed == 0:
+		return TournamentStateCompleted
+	case played == 0:
+		return TournamentStateNotStarted
+	default:
+		return TournamentStateInProgress
+	}
+}
+
+func isMatchPlayed(match lolesports.Match) bool {
+	for _, team := range match.Teams {
+		if team.Result != nil && team.Result.Outcome != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -4,14 +4,28 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/matthieugusmini/go-lolesports"
+	"golang.org/x/sync/singleflight"
+
 	"github.com/matthieugusmini/rift/internal/timeutil"
 )
 
 const currentSeasonSplitsCacheKey = "current_splits"
 
+// TTLs of the standings depending on the state of their tournaments.
+const (
+	// Standings of completed tournaments rarely change, but the state is only inferred
+	// from the matches known so far: stages or rounds without matches yet
+	// would make an ongoing tournament look completed, so they are refetched once a week.
+	standingsCompletedTTL  = 7 * 24 * time.Hour
+	standingsInProgressTTL = 5 * time.Minute
+	standingsNotStartedTTL = 3 * time.Hour
+)
+
 // LoLEsportsAPIClient represents an API client to retrieve data from LoL Esports.
 type LoLEsportsAPIClient interface {
 	GetStandings(ctx context.Context, tournamentIDs []string) ([]lolesports.Standings, error)
@@ -27,7 +41,11 @@ type LoLEsportsLoader struct {
 	apiClient      LoLEsportsAPIClient
 	standingsCache Cache[[]lolesports.Standings]
 	splitsCache    Cache[[]lolesports.Split]
-	logger         *slog.Logger
+	scheduleCache  Cache[lolesports.Schedule]
+	staleness      staleness
+	// Deduplicates the identical loads in flight.
+	group  singleflight.Group
+	logger *slog.Logger
 }
 
 // NewLoLEsportsLoader creates a new instance of a [Loader] which loads LoLEsports
@@ -37,28 +55,46 @@ func NewLoLEsportsLoader(
 	apiClient LoLEsportsAPIClient,
 	standingsCache Cache[[]lolesports.Standings],
 	splitsCache Cache[[]lolesports.Split],
+	scheduleCache Cache[lolesports.Schedule],
 	logger *slog.Logger,
 ) *LoLEsportsLoader {
 	return &LoLEsportsLoader{
 		apiClient:      apiClient,
 		standingsCache: standingsCache,
 		splitsCache:    splitsCache,
+		scheduleCache:  scheduleCache,
 		logger:         logger,
 	}
 }
 
 // LoadStandingsByTournamentIDs tries to load all the standings for all the tournamentIDs
 // from the underlying cache first and if not found, fetches them from the API.
+// If the API cannot be reached, expired standings from the cache are returned instead.
+//
+// Concurrent loads of the same standings share a single call to the API.
 //
-// An error is returned only if the client cannot load the standings.
+// An error is returned only if the standings cannot be loaded from any source.
 // Errors returned by the cache are not forwarded and are just logged instead.
 func (l *LoLEsportsLoader) LoadStandingsByTournamentIDs(
 	ctx context.Context,
 	tournamentIDs []string,
 ) ([]lolesports.Standings, error) {
-	var standings []lolesports.Standings
-
 	key := makeStandingsCacheKey(tournamentIDs)
+	return coalesce(
+		ctx,
+		&l.group,
+		"standings:"+key,
+		func(ctx context.Context) ([]lolesports.Standings, error) {
+			return l.loadStandings(ctx, key, tournamentIDs)
+		},
+	)
+}
+
+func (l *LoLEsportsLoader) loadStandings(
+	ctx context.Context,
+	key string,
+	tournamentIDs []string,
+) ([]lolesports.Standings, error) {
 	standings, ok, err := l.standingsCache.Get(key)
 	if err != nil {
 		l.logger.Debug(
@@ -73,10 +109,19 @@ func (l *LoLEsportsLoader) LoadStandingsByTournamentIDs(
 
 	standings, err = l.apiClient.GetStandings(ctx, tournamentIDs)
 	if err != nil {
+		if standings, ok := getStale(l.standingsCache, key, &l.staleness, l.logger); ok {
+			return standings, nil
+		}
 		return nil, err
 	}
+	l.staleness.markFresh()
 
-	if err := l.standingsCache.Set(key, standings); err != nil {
+	if ttl, ok := standingsTTL(standings); ok {
+		err = l.standingsCache.SetWithTTL(key, standings, ttl)
+	} else {
+		err = l.standingsCache.Set(key, standings)
+	}
+	if err != nil {
 		l.logger.Warn(
 			"Failed to set standings in cache",
 			slog.Any("err", err),
@@ -89,11 +134,23 @@ func (l *LoLEsportsLoader) LoadStandingsByTournamentIDs(
 
 // LoadCurrentSeasonSplits tries to load all the splits for the current season
 // from the underlying cache first and if not found, fetches them from the API.
+// If the API cannot be reached, expired splits from the cache are returned instead.
+//
+// Concurrent loads share a single call to the API.
 //
-// An error is returned only if the client cannot load the standings.
+// An error is returned only if the splits cannot be loaded from any source.
 // Errors returned by the cache are not forwarded and are just logged instead.
 func (l *LoLEsportsLoader) LoadCurrentSeasonSplits(
 	ctx context.Context,
+) ([]lolesports.Split, error) {
+	key := "splits:" + currentSeasonSplitsCacheKey
+	return coalesce(ctx, &l.group, key, func(ctx context.Context) ([]lolesports.Split, error) {
+		return l.loadCurrentSeasonSplits(ctx)
+	})
+}
+
+func (l *LoLEsportsLoader) loadCurrentSeasonSplits(
+	ctx context.Context,
 ) ([]lolesports.Split, error) {
 	splits, ok, err := l.splitsCache.Get(currentSeasonSplitsCacheKey)
 	if err != nil {
@@ -108,8 +165,13 @@ func (l *LoLEsportsLoader) LoadCurrentSeasonSplits(
 
 	seasons, err := l.apiClient.GetSeasons(ctx, nil)
 	if err != nil {
+		splits, ok := getStale(l.splitsCache, currentSeasonSplitsCacheKey, &l.staleness, l.logger)
+		if ok {
+			return splits, nil
+		}
 		return nil, fmt.Errorf("could not fetch seasons: %w", err)
 	}
+	l.staleness.markFresh()
 
 	var currentSeason lolesports.Season
 	for _, season := range seasons {
@@ -129,23 +191,126 @@ func (l *LoLEsportsLoader) LoadCurrentSeasonSplits(
 	return currentSeason.Splits, nil
 }
 
-// GetSchedule fetches the schedule from the API.
+// GetSchedule fetches the schedule from the API and stores the fetched page
+// in the underlying cache so it can be displayed right away next time
+// using [LoLEsportsLoader.GetCachedSchedule].
 //
 // Optionally options can be passed to fetch specific pages or
 // to fetch only events related to certain leagues.
 //
+// If the API cannot be reached, the page stored in the cache is returned instead
+// even if it has expired. Concurrent fetches of the same page share a single call to the API.
+//
 // An error is returned if it cannot fetch the data.
+// Errors returned by the cache are not forwarded and are just logged instead.
 func (l *LoLEsportsLoader) GetSchedule(
 	ctx context.Context,
 	opts *lolesports.GetScheduleOptions,
 ) (lolesports.Schedule, error) {
-	return l.apiClient.GetSchedule(ctx, opts)
+	key := makeScheduleCacheKey(opts)
+	return coalesce(
+		ctx,
+		&l.group,
+		"schedule:"+key,
+		func(ctx context.Context) (lolesports.Schedule, error) {
+			return l.getSchedule(ctx, key, opts)
+		},
+	)
+}
+
+func (l *LoLEsportsLoader) getSchedule(
+	ctx context.Context,
+	key string,
+	opts *lolesports.GetScheduleOptions,
+) (lolesports.Schedule, error) {
+	schedule, err := l.apiClient.GetSchedule(ctx, opts)
+	if err != nil {
+		if schedule, ok := getStale(l.scheduleCache, key, &l.staleness, l.logger); ok {
+			return schedule, nil
+		}
+		return lolesports.Schedule{}, err
+	}
+	l.staleness.markFresh()
+
+	if err := l.scheduleCache.Set(key, schedule); err != nil {
+		l.logger.Warn(
+			"Failed to set schedule in cache",
+			slog.Any("err", err),
+			slog.String("key", key),
+		)
+	}
+
+	return schedule, nil
+}
+
+// GetCachedSchedule returns the last schedule page fetched with the same options
+// from the underlying cache without performing any I/O on the network.
+//
+// Additionally a boolean is returned to indicate whether the page was found in the cache.
+// Errors returned by the cache are not forwarded and are just logged instead.
+func (l *LoLEsportsLoader) GetCachedSchedule(
+	opts *lolesports.GetScheduleOptions,
+) (lolesports.Schedule, bool) {
+	key := makeScheduleCacheKey(opts)
+	schedule, ok, err := l.scheduleCache.Get(key)
+	if err != nil {
+		l.logger.Debug(
+			"Schedule not present in cache",
+			slog.Any("err", err),
+			slog.String("key", key),
+		)
+	}
+	return schedule, ok
+}
+
+// StaleSince returns the time at which the oldest expired data served by the loader,
+// because fresh data could not be fetched, was stored.
+//
+// Additionally a boolean is returned to indicate whether stale data has been served
+// since the last successful fetch.
+func (l *LoLEsportsLoader) StaleSince() (time.Time, bool) {
+	return l.staleness.get()
+}
+
+// standingsTTL returns the TTL of the standings depending on the state of their tournaments.
+//
+// Additionally a boolean is returned to indicate whether the state is known,
+// otherwise the default TTL of the cache should be used.
+func standingsTTL(standings []lolesports.Standings) (time.Duration, bool) {
+	switch ComputeStandingsState(standings) {
+	case TournamentStateCompleted:
+		return standingsCompletedTTL, true
+	case TournamentStateInProgress:
+		return standingsInProgressTTL, true
+	case TournamentStateNotStarted:
+		return standingsNotStartedTTL, true
+	default:
+		return 0, false
+	}
 }
 
 func makeStandingsCacheKey(tournamentIDs []string) string {
 	return strings.Join(tournamentIDs, ":")
 }
 
+// makeScheduleCacheKey returns a key identifying a schedule page by its league filter
+// and page token, e.g. "98767991302996019,98767991310872058:b2xkZXI6OjExMzQ3".
+func makeScheduleCacheKey(opts *lolesports.GetScheduleOptions) string {
+	if opts == nil {
+		return ":"
+	}
+
+	leagueIDs := slices.Clone(opts.LeagueIDs)
+	slices.Sort(leagueIDs)
+
+	var pageToken string
+	if opts.PageToken != nil {
+		pageToken = *opts.PageToken
+	}
+
+	return strings.Join(leagueIDs, ",") + ":" + pageToken
+}
+
 func isCurrentSeason(season lolesports.Season) bool {
 	return season.Name == "lolesports" &&
 		timeutil.IsCurrentTimeBetween(season.StartTime, season.EndTime)
@@ -4,8 +4,10 @@ import (
 	"context"
 	"log/slog"
 	"testing"
+	"time"
 
 	"github.com/matthieugusmini/go-lolesports"
+	"github.com/matthieugusmini/rift/internal/cache"
 	"github.com/matthieugusmini/rift/internal/rift"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -26,6 +28,7 @@ func TestLoLEsportsLoader_LoadStandingsByTournamentIDs(t *testing.T) {
 			stubLoLEsportsAPIClient,
 			fakeStandingsCache,
 			fakeSplitsCache,
+			newFakeCache[lolesports.Schedule](),
 			slog.Default(),
 		)
 
@@ -43,6 +46,7 @@ func TestLoLEsportsLoader_LoadStandingsByTournamentIDs(t *testing.T) {
 			stubLoLEsportsAPIClient,
 			fakeStandingsCache,
 			fakeSplitsCache,
+			newFakeCache[lolesports.Schedule](),
 			slog.Default(),
 		)
 
@@ -53,6 +57,61 @@ func TestLoLEsportsLoader_LoadStandingsByTournamentIDs(t *testing.T) {
 		// Assert that the cache has been updated
 		_, ok := fakeStandingsCache.entries[cacheKey]
 		assert.True(t, ok)
+		// Completed standings are cached for days.
+		ttl, ok := fakeStandingsCache.ttls[cacheKey]
+		assert.True(t, ok)
+		assert.Equal(t, 7*24*time.Hour, ttl)
+	})
+
+	t.Run("caches in progress standings for minutes", func(t *testing.T) {
+		stubLoLEsportsAPIClient := newStubLoLEsportsAPIClient()
+		stubLoLEsportsAPIClient.standings = []lolesports.Standings{
+			{
+				Stages: []lolesports.Stage{
+					{
+						Sections: []lolesports.Section{
+							{Matches: []lolesports.Match{testPlayedMatch, testUnplayedMatch}},
+						},
+					},
+				},
+			},
+		}
+		fakeStandingsCache := newFakeCache[[]lolesports.Standings]()
+		loader := rift.NewLoLEsportsLoader(
+			stubLoLEsportsAPIClient,
+			fakeStandingsCache,
+			newFakeCache[[]lolesports.Split](),
+			newFakeCache[lolesports.Schedule](),
+			slog.Default(),
+		)
+
+		_, err := loader.LoadStandingsByTournamentIDs(t.Context(), tournamentIDs)
+
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Minute, fakeStandingsCache.ttls[cacheKey])
+	})
+
+	t.Run("returns expired standings of a real cache if API fails", func(t *testing.T) {
+		expiringCache := cache.New[[]lolesports.Standings](
+			cache.NewMemoryBackend(),
+			"standings",
+			-time.Second,
+		)
+		require.NoError(t, expiringCache.Set(cacheKey, testStandings))
+		loader := rift.NewLoLEsportsLoader(
+			newNotFoundLoLEsportsAPIClient(),
+			expiringCache,
+			newFakeCache[[]lolesports.Split](),
+			newFakeCache[lolesports.Schedule](),
+			slog.Default(),
+		)
+
+		got, err := loader.LoadStandingsByTournamentIDs(t.Context(), tournamentIDs)
+
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		_, isStale := loader.StaleSince()
+		assert.True(t, isStale)
 	})
 
 	t.Run("returns error if not in cache and API fails", func(t *testing.T) {
@@ -63,6 +122,7 @@ func TestLoLEsportsLoader_LoadStandingsByTournamentIDs(t *testing.T) {
 			stubLoLEsportsAPIClient,
 			fakeStandingsCache,
 			fakeSplitsCache,
+			newFakeCache[lolesports.Schedule](),
 			slog.Default(),
 		)
 
@@ -71,6 +131,27 @@ func TestLoLEsportsLoader_LoadStandingsByTournamentIDs(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("returns stale standings if API fails", func(t *testing.T) {
+		stubLoLEsportsAPIClient := newNotFoundLoLEsportsAPIClient()
+		fakeStandingsCache := newFakeCache[[]lolesports.Standings]()
+		fakeStandingsCache.staleEntries = map[string][]lolesports.Standings{cacheKey: testStandings}
+		loader := rift.NewLoLEsportsLoader(
+			stubLoLEsportsAPIClient,
+			fakeStandingsCache,
+			newFakeCache[[]lolesports.Split](),
+			newFakeCache[lolesports.Schedule](),
+			slog.Default(),
+		)
+
+		got, err := loader.LoadStandingsByTournamentIDs(t.Context(), tournamentIDs)
+
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		staleSince, ok := loader.StaleSince()
+		assert.True(t, ok)
+		assert.Equal(t, testStaleSince, staleSince)
+	})
+
 	t.Run("fetch from API if fails to get in cache", func(t *testing.T) {
 		stubLoLEsportsAPIClient := newStubLoLEsportsAPIClient()
 		fakeStandingsCache := newFakeCache[[]lolesports.Standings]()
@@ -80,6 +161,7 @@ func TestLoLEsportsLoader_LoadStandingsByTournamentIDs(t *testing.T) {
 			stubLoLEsportsAPIClient,
 			fakeStandingsCache,
 			fakeSplitsCache,
+			newFakeCache[lolesports.Schedule](),
 			slog.Default(),
 		)
 
@@ -101,6 +183,7 @@ func TestLoLEsportsLoader_LoadStandingsByTournamentIDs(t *testing.T) {
 			stubLoLEsportsAPIClient,
 			fakeStandingsCache,
 			fakeSplitsCache,
+			newFakeCache[lolesports.Schedule](),
 			slog.Default(),
 		)
 
@@ -111,6 +194,139 @@ func TestLoLEsportsLoader_LoadStandingsByTournamentIDs(t *testing.T) {
 	})
 }
 
+func TestLoLEsportsLoader_GetSchedule(t *testing.T) {
+	pageToken := "b2xkZXI6OjExMzQ3"
+	opts := &lolesports.GetScheduleOptions{
+		LeagueIDs: []string{"lec", "lck"},
+		PageToken: &pageToken,
+	}
+	cacheKey := "lck,lec:" + pageToken
+	want := testSchedule
+
+	t.Run("fetches from API and update cache", func(t *testing.T) {
+		stubLoLEsportsAPIClient := newStubLoLEsportsAPIClient()
+		fakeScheduleCache := newFakeCache[lolesports.Schedule]()
+		loader := rift.NewLoLEsportsLoader(
+			stubLoLEsportsAPIClient,
+			newFakeCache[[]lolesports.Standings](),
+			newFakeCache[[]lolesports.Split](),
+			fakeScheduleCache,
+			slog.Default(),
+		)
+
+		got, err := loader.GetSchedule(t.Context(), opts)
+
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		// Assert that the cache has been updated
+		_, ok := fakeScheduleCache.entries[cacheKey]
+		assert.True(t, ok)
+	})
+
+	t.Run("returns stale page if API fails", func(t *testing.T) {
+		stubLoLEsportsAPIClient := newNotFoundLoLEsportsAPIClient()
+		fakeScheduleCache := newFakeCache[lolesports.Schedule]()
+		fakeScheduleCache.staleEntries = map[string]lolesports.Schedule{cacheKey: testSchedule}
+		loader := rift.NewLoLEsportsLoader(
+			stubLoLEsportsAPIClient,
+			newFakeCache[[]lolesports.Standings](),
+			newFakeCache[[]lolesports.Split](),
+			fakeScheduleCache,
+			slog.Default(),
+		)
+
+		got, err := loader.GetSchedule(t.Context(), opts)
+
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		_, ok := loader.StaleSince()
+		assert.True(t, ok)
+	})
+
+	t.Run("returns error if API fails and not cached", func(t *testing.T) {
+		stubLoLEsportsAPIClient := newNotFoundLoLEsportsAPIClient()
+		loader := rift.NewLoLEsportsLoader(
+			stubLoLEsportsAPIClient,
+			newFakeCache[[]lolesports.Standings](),
+			newFakeCache[[]lolesports.Split](),
+			newFakeCache[lolesports.Schedule](),
+			slog.Default(),
+		)
+
+		_, err := loader.GetSchedule(t.Context(), opts)
+
+		assert.Error(t, err)
+		_, ok := loader.StaleSince()
+		assert.False(t, ok)
+	})
+
+	t.Run("returns result if cannot update cache", func(t *testing.T) {
+		stubLoLEsportsAPIClient := newStubLoLEsportsAPIClient()
+		fakeScheduleCache := newFakeCache[lolesports.Schedule]()
+		fakeScheduleCache.setErr = errCacheSet
+		loader := rift.NewLoLEsportsLoader(
+			stubLoLEsportsAPIClient,
+			newFakeCache[[]lolesports.Standings](),
+			newFakeCache[[]lolesports.Split](),
+			fakeScheduleCache,
+			slog.Default(),
+		)
+
+		got, err := loader.GetSchedule(t.Context(), opts)
+
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+}
+
+func TestLoLEsportsLoader_GetCachedSchedule(t *testing.T) {
+	t.Run("returns cached initial page", func(t *testing.T) {
+		fakeScheduleCache := newFakeCacheWith(
+			map[string]lolesports.Schedule{":": testSchedule},
+		)
+		loader := rift.NewLoLEsportsLoader(
+			newNotFoundLoLEsportsAPIClient(),
+			newFakeCache[[]lolesports.Standings](),
+			newFakeCache[[]lolesports.Split](),
+			fakeScheduleCache,
+			slog.Default(),
+		)
+
+		got, ok := loader.GetCachedSchedule(&lolesports.GetScheduleOptions{})
+
+		assert.True(t, ok)
+		assert.Equal(t, testSchedule, got)
+	})
+
+	t.Run("returns false if not cached", func(t *testing.T) {
+		fakeScheduleCache := newFakeCache[lolesports.Schedule]()
+		fakeScheduleCache.getErr = errCacheGet
+		loader := rift.NewLoLEsportsLoader(
+			newStubLoLEsportsAPIClient(),
+			newFakeCache[[]lolesports.Standings](),
+			newFakeCache[[]lolesports.Split](),
+			fakeScheduleCache,
+			slog.Default(),
+		)
+
+		_, ok := loader.GetCachedSchedule(nil)
+
+		assert.False(t, ok)
+	})
+}
+
+var testSchedule = lolesports.Schedule{
+	Pages: lolesports.Pages{Older: "older", Newer: "newer"},
+	Events: []lolesports.Event{
+		{
+			BlockName: "Week 1",
+			Type:      lolesports.EventTypeMatch,
+			State:     lolesports.EventStateCompleted,
+			Match:     testStandings[0].Stages[0].Sections[0].Matches[0],
+		},
+	},
+}
+
 var testStandings = []lolesports.Standings{
 	{
 		Stages: []lolesports.Stage{
@@ -159,11 +375,15 @@ var testStandings = []lolesports.Standings{
 type stubLoLEsportsAPIClient struct {
 	standings []lolesports.Standings
 	seasons   []lolesports.Season
+	schedule  lolesports.Schedule
 	err       error
 }
 
 func newStubLoLEsportsAPIClient() *stubLoLEsportsAPIClient {
-	return &stubLoLEsportsAPIClient{standings: testStandings}
+	return &stubLoLEsportsAPIClient{
+		standings: testStandings,
+		schedule:  testSchedule,
+	}
 }
 
 func newNotFoundLoLEsportsAPIClient() *stubLoLEsportsAPIClient {
@@ -194,7 +414,10 @@ func (c *stubLoLEsportsAPIClient) GetSchedule(
 	ctx context.Context,
 	opts *lolesports.GetScheduleOptions,
 ) (lolesports.Schedule, error) {
-	return lolesports.Schedule{}, nil
+	if c.err != nil {
+		return lolesports.Schedule{}, c.err
+	}
+	return c.schedule, nil
 }
 
 func pointer[T any](v T) *T { return &v }
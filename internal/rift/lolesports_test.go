@@ -0,0 +1,62 @@
+package rift_test
+
+import (
+	"testing"
+
+	"github.com/matthieugusmini/go-lolesports"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matthieugusmini/rift/internal/rift"
+)
+
+func TestComputeStandingsState(t *testing.T) {
+	tt := []struct {
+		name    string
+		matches []lolesports.Match
+		want    rift.TournamentState
+	}{
+		{name: "no match", want: rift.TournamentStateUnknown},
+		{
+			name:    "all matches played",
+			matches: []lolesports.Match{testPlayedMatch, testPlayedMatch},
+			want:    rift.TournamentStateCompleted,
+		},
+		{
+			name:    "some matches played",
+			matches: []lolesports.Match{testPlayedMatch, testUnplayedMatch},
+			want:    rift.TournamentStateInProgress,
+		},
+		{
+			name:    "no match played",
+			matches: []lolesports.Match{testUnplayedMatch},
+			want:    rift.TournamentStateNotStarted,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			standings := []lolesports.Standings{
+				{
+					Stages: []lolesports.Stage{
+						{Sections: []lolesports.Section{{Matches: tc.matches}}},
+					},
+				},
+			}
+
+			got := rift.ComputeStandingsState(standings)
+
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+var (
+	testPlayedMatch = lolesports.Match{
+		Teams: []lolesports.Team{
+			{Code: "G2", Result: &lolesports.Result{Outcome: pointer("win"), GameWins: 3}},
+			{Code: "FNC", Result: &lolesports.Result{Outcome: pointer("loss"), GameWins: 1}},
+		},
+	}
+	testUnplayedMatch = lolesports.Match{
+		Teams: []lolesports.Team{{Code: "G2"}, {Code: "FNC"}},
+	}
+)
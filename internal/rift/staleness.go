@@ -0,0 +1,70 @@
+package rift
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// staleness keeps track of the stale data served by a loader when fresh data
+// could not be fetched (e.g. network unavailable).
+//
+// It is safe for concurrent use.
+type staleness struct {
+	mu sync.Mutex
+	// Time at which the oldest stale data served since the last successful fetch
+	// was stored. Zero if no stale data has been served.
+	since time.Time
+}
+
+// markStale records that data stored at updatedAt has been served.
+func (s *staleness) markStale(updatedAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.since.IsZero() || updatedAt.Before(s.since) {
+		s.since = updatedAt
+	}
+}
+
+// markFresh records that fresh data has been successfully fetched.
+func (s *staleness) markFresh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.since = time.Time{}
+}
+
+func (s *staleness) get() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.since, !s.since.IsZero()
+}
+
+// getStale returns the value associated to key in the cache even if it has expired
+// and records it in s as stale data being served.
+//
+// Errors returned by the cache are not forwarded and are just logged instead.
+func getStale[T any](cache Cache[T], key string, s *staleness, logger *slog.Logger) (T, bool) {
+	value, updatedAt, ok, err := cache.GetStale(key)
+	if err != nil {
+		logger.Debug(
+			"Stale entry not present in cache",
+			slog.Any("err", err),
+			slog.String("key", key),
+		)
+	}
+	if !ok {
+		return value, false
+	}
+
+	logger.Info(
+		"Serving stale data from cache",
+		slog.String("key", key),
+		slog.Time("updatedAt", updatedAt),
+	)
+	s.markStale(updatedAt)
+
+	return value, true
+}
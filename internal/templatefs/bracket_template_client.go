@@ -0,0 +1,103 @@
+// Package templatefs provides a client to retrieve bracket templates from a file system,
+// e.g. a local directory used to author templates before they are published.
+package templatefs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/matthieugusmini/rift/internal/rift"
+)
+
+const bracketTypeByStageIDFilename = "bracket-type-by-stage-id.json"
+
+// BracketTemplateClient handles reading bracket templates from JSON config files
+// laid out the same way as in the GitHub repository of the templates:
+//
+//	bracket-type-by-stage-id.json
+//	8SE.json
+//	...
+type BracketTemplateClient struct {
+	fsys fs.FS
+}
+
+// NewBracketTemplateClient creates a new instance of [BracketTemplateClient]
+// reading the files from fsys (e.g. [os.DirFS]).
+func NewBracketTemplateClient(fsys fs.FS) *BracketTemplateClient {
+	return &BracketTemplateClient{fsys: fsys}
+}
+
+// ListAvailableStageIDs returns the list of stage ids
+// which have a bracket template associated with them.
+//
+// No stage id is returned if the file system has no bracket-type-by-stage-id.json file.
+// An error is returned if it cannot be read or decoded.
+func (c *BracketTemplateClient) ListAvailableStageIDs(_ context.Context) ([]string, error) {
+	bracketTypeByStageID, err := c.getBracketTemplateMapper()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(bracketTypeByStageID))
+	for k := range bracketTypeByStageID {
+		keys = append(keys, k)
+	}
+
+	return keys, nil
+}
+
+// GetTemplateByStageID returns the bracket template associated with the given stage id.
+//
+// An error wrapping [rift.ErrStageNotSupported] is returned if no bracket template
+// is associated with the stage id. An error is also returned if the files cannot be
+// read or decoded.
+func (c *BracketTemplateClient) GetTemplateByStageID(
+	_ context.Context,
+	stageID string,
+) (rift.BracketTemplate, error) {
+	bracketTypeByStageID, err := c.getBracketTemplateMapper()
+	if err != nil {
+		return rift.BracketTemplate{}, err
+	}
+
+	bracketType, ok := bracketTypeByStageID[stageID]
+	if !ok {
+		return rift.BracketTemplate{}, fmt.Errorf("%w: %q", rift.ErrStageNotSupported, stageID)
+	}
+
+	var tmpl rift.BracketTemplate
+	if err := c.read(bracketType+".json", &tmpl); err != nil {
+		return rift.BracketTemplate{}, err
+	}
+
+	return tmpl, nil
+}
+
+func (c *BracketTemplateClient) getBracketTemplateMapper() (map[string]string, error) {
+	var data map[string]string
+	err := c.read(bracketTypeByStageIDFilename, &data)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (c *BracketTemplateClient) read(name string, data any) error {
+	b, err := fs.ReadFile(c.fsys, name)
+	if err != nil {
+		return fmt.Errorf("could not read %s: %w", name, err)
+	}
+
+	if err := json.Unmarshal(b, data); err != nil {
+		return fmt.Errorf("could not decode %s: %w", name, err)
+	}
+
+	return nil
+}
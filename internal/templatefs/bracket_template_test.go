@@ -0,0 +1,100 @@
+package templatefs_test
+
+import (
+	"flag"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matthieugusmini/rift/internal/rift"
+	"github.com/matthieugusmini/rift/internal/templatefs"
+)
+
+func TestBracketTemplateClient_ListAvailableStageIDs(t *testing.T) {
+	t.Run("returns available stage ids", func(t *testing.T) {
+		client := templatefs.NewBracketTemplateClient(testFS)
+
+		got, err := client.ListAvailableStageIDs(t.Context())
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"1", "2"}, got)
+	})
+
+	t.Run("returns no stage ids without mapper", func(t *testing.T) {
+		client := templatefs.NewBracketTemplateClient(fstest.MapFS{})
+
+		got, err := client.ListAvailableStageIDs(t.Context())
+
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("malformated JSON returns error", func(t *testing.T) {
+		client := templatefs.NewBracketTemplateClient(fstest.MapFS{
+			"bracket-type-by-stage-id.json": {Data: []byte("malformated JSON")},
+		})
+
+		_, err := client.ListAvailableStageIDs(t.Context())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestBracketTemplateClient_GetTemplateByStageID(t *testing.T) {
+	t.Run("returns bracket template", func(t *testing.T) {
+		client := templatefs.NewBracketTemplateClient(testFS)
+
+		got, err := client.GetTemplateByStageID(t.Context(), "1")
+
+		require.NoError(t, err)
+		want := rift.BracketTemplate{Rounds: []rift.Round{{Title: "Final"}}}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("stage id not supported returns error", func(t *testing.T) {
+		client := templatefs.NewBracketTemplateClient(testFS)
+
+		_, err := client.GetTemplateByStageID(t.Context(), "3")
+
+		assert.ErrorIs(t, err, rift.ErrStageNotSupported)
+	})
+
+	t.Run("missing template file returns error", func(t *testing.T) {
+		client := templatefs.NewBracketTemplateClient(testFS)
+
+		_, err := client.GetTemplateByStageID(t.Context(), "2")
+
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, rift.ErrStageNotSupported)
+	})
+}
+
+var release = flag.Bool(
+	"release",
+	false,
+	"require the snapshot to hold templates, as when shipping it in a release",
+)
+
+func TestSnapshot(t *testing.T) {
+	client := templatefs.NewBracketTemplateClient(templatefs.Snapshot())
+
+	stageIDs, err := client.ListAvailableStageIDs(t.Context())
+	require.NoError(t, err)
+	if *release {
+		require.NotEmpty(t, stageIDs, "the snapshot must be refreshed before a release")
+	}
+
+	// Every stage of the snapshot must be associated with a non-empty template.
+	for _, stageID := range stageIDs {
+		tmpl, err := client.GetTemplateByStageID(t.Context(), stageID)
+		assert.NoError(t, err, "stage %s", stageID)
+		assert.NotEmpty(t, tmpl.Rounds, "stage %s", stageID)
+	}
+}
+
+var testFS = fstest.MapFS{
+	"bracket-type-by-stage-id.json": {Data: []byte(`{"1": "2SE", "2": "4SE"}`)},
+	"2SE.json":                      {Data: []byte(`{"rounds": [{"title": "Final"}]}`)},
+}
@@ -0,0 +1,23 @@
+package templatefs
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// snapshot holds a copy of the lolesports-bracket-templates repository
+// taken at build time, refreshed with `task templates:refresh`.
+//
+//go:embed snapshot/*.json
+var snapshot embed.FS
+
+// Snapshot returns the file system of the bracket templates shipped in the binary,
+// to be used when the published ones cannot be fetched.
+func Snapshot() fs.FS {
+	sub, err := fs.Sub(snapshot, "snapshot")
+	if err != nil {
+		// Only fails if the directory name is invalid.
+		panic(err)
+	}
+	return sub
+}
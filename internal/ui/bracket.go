@@ -28,6 +28,16 @@ const (
 	topLeftCorner     = "┌"
 	bottomRightCorner = "┘"
 	bottomLeftCorner  = "└"
+
+	// Joins the vertical line of an L-shaped link to a horizontal line.
+	upJunction   = "┴"
+	downJunction = "┬"
+
+	// Drawn for the links of the losers going to the lower bracket.
+	dashedHorizontalLine = "╌"
+	dashedVerticalLine   = "╎"
+	// Drawn for the links where the teams are reseeded before the next round.
+	reseedMarker = "R"
 )
 
 const (
@@ -38,11 +48,14 @@ const (
 type bracketPageKeyMap struct {
 	baseKeyMap
 
-	Up       key.Binding
-	Down     key.Binding
-	Left     key.Binding
-	Right    key.Binding
-	Previous key.Binding
+	Up          key.Binding
+	Down        key.Binding
+	Left        key.Binding
+	Right       key.Binding
+	Select      key.Binding
+	Reveal      key.Binding
+	RevealRound key.Binding
+	Previous    key.Binding
 }
 
 func newDefaultBracketPageKeyMap() bracketPageKeyMap {
@@ -58,11 +71,23 @@ func newDefaultBracketPageKeyMap() bracketPageKeyMap {
 		),
 		Right: key.NewBinding(
 			key.WithKeys("right", "l"),
-			key.WithHelp("→/l", "right"),
+			key.WithHelp("→/l", "next round"),
 		),
 		Left: key.NewBinding(
 			key.WithKeys("left", "h"),
-			key.WithHelp("←/h", "left"),
+			key.WithHelp("←/h", "prev round"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "details"),
+		),
+		Reveal: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "reveal match"),
+		),
+		RevealRound: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "reveal round"),
 		),
 		Previous: key.NewBinding(
 			key.WithKeys("esc"),
@@ -74,12 +99,16 @@ func newDefaultBracketPageKeyMap() bracketPageKeyMap {
 type bracketPageStyles struct {
 	roundTitle       lipgloss.Style
 	match            lipgloss.Style
+	selectedMatch    lipgloss.Style
+	pathMatch        lipgloss.Style
 	noTeamResult     lipgloss.Style
 	loserTeamName    lipgloss.Style
 	loserTeamResult  lipgloss.Style
 	winnerTeamName   lipgloss.Style
 	winnerTeamResult lipgloss.Style
 	link             lipgloss.Style
+	matchDetail      lipgloss.Style
+	matchDetailTitle lipgloss.Style
 	help             lipgloss.Style
 }
 
@@ -94,6 +123,13 @@ func newDefaultBracketPageStyles() (s bracketPageStyles) {
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(borderPrimaryColor)
 
+	s.selectedMatch = s.match.
+		Border(lipgloss.ThickBorder()).
+		BorderForeground(selectedColor)
+
+	s.pathMatch = s.match.
+		BorderForeground(selectedColor)
+
 	s.noTeamResult = lipgloss.NewStyle().
 		Foreground(textPrimaryColor)
 
@@ -115,6 +151,13 @@ func newDefaultBracketPageStyles() (s bracketPageStyles) {
 
 	s.link = lipgloss.NewStyle().Foreground(borderSecondaryColor)
 
+	s.matchDetail = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(selectedColor).
+		Padding(1, 2)
+
+	s.matchDetailTitle = s.roundTitle
+
 	s.help = lipgloss.NewStyle().Padding(1, 0, 0, 2)
 
 	return s
@@ -124,15 +167,24 @@ type bracketPage struct {
 	width, height int
 	template      rift.BracketTemplate
 	matches       []lolesports.Match
-	viewport      viewport.Model
-	help          help.Model
-	keyMap        bracketPageKeyMap
-	styles        bracketPageStyles
+	cursor        bracketCursor
+	spoilers      *spoilerGuard
+	// Version of the spoilers the content was rendered with.
+	spoilersVersion int
+	// Whether the detail of the selected match is displayed.
+	showMatchDetail bool
+	viewport        viewport.Model
+	// Horizontal scroll position of the viewport which does not expose it.
+	xOffset int
+	help    help.Model
+	keyMap  bracketPageKeyMap
+	styles  bracketPageStyles
 }
 
 func newBracketPage(
 	template rift.BracketTemplate,
 	matches []lolesports.Match,
+	spoilers *spoilerGuard,
 	width, height int,
 ) *bracketPage {
 	m := &bracketPage{
@@ -140,10 +192,17 @@ func newBracketPage(
 		matches:  matches,
 		width:    width,
 		height:   height,
+		cursor:   newBracketCursor(template, matches),
+		spoilers: spoilers,
 		help:     help.New(),
 		keyMap:   newDefaultBracketPageKeyMap(),
 		styles:   newDefaultBracketPageStyles(),
 	}
+	spoilers.restore(matchIDs(matches)...)
+	// Starting on the first match to play would tell how far the bracket went.
+	if spoilers.hidesAnyResult(matches) {
+		m.cursor.selected = 0
+	}
 
 	m.initViewport()
 
@@ -153,6 +212,8 @@ func newBracketPage(
 func renderBracket(
 	tmpl rift.BracketTemplate,
 	matches []lolesports.Match,
+	highlights map[int]matchHighlight,
+	spoilers *spoilerGuard,
 	width, height int,
 	styles bracketPageStyles,
 ) string {
@@ -185,7 +246,12 @@ func renderBracket(
 
 			switch match.DisplayType {
 			case rift.DisplayTypeMatch:
-				roundView += drawMatch(matches[matchIndex], matchWidth, styles)
+				roundView += drawMatch(
+					spoilers.redactMatch(matches[matchIndex], matches),
+					matchWidth,
+					highlights[matchIndex],
+					styles,
+				)
 				matchIndex++
 			case rift.DisplayTypeHorizontalLine:
 				line := styles.link.Render(horizontalLine)
@@ -218,10 +284,45 @@ func renderBracket(
 func (m *bracketPage) Update(msg tea.Msg) (*bracketPage, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.showMatchDetail {
+			if key.Matches(msg, m.keyMap.Previous, m.keyMap.Select) {
+				m.showMatchDetail = false
+			}
+			return m, nil
+		}
+
 		switch {
 		case key.Matches(msg, m.keyMap.ShowFullHelp),
 			key.Matches(msg, m.keyMap.CloseFullHelp):
 			m.toggleFullHelp()
+
+		case m.cursor.isEmpty():
+			break
+
+		case key.Matches(msg, m.keyMap.Up):
+			m.cursor.moveToSibling(-1)
+			m.refreshContent()
+
+		case key.Matches(msg, m.keyMap.Down):
+			m.cursor.moveToSibling(1)
+			m.refreshContent()
+
+		case key.Matches(msg, m.keyMap.Left):
+			m.cursor.moveToPreviousRound()
+			m.refreshContent()
+
+		case key.Matches(msg, m.keyMap.Right):
+			m.cursor.moveToNextRound()
+			m.refreshContent()
+
+		case key.Matches(msg, m.keyMap.Select):
+			m.showMatchDetail = true
+
+		case key.Matches(msg, m.keyMap.Reveal):
+			m.spoilers.reveal(m.cursor.selectedMatch().ID)
+
+		case key.Matches(msg, m.keyMap.RevealRound):
+			m.spoilers.reveal(m.selectedRoundMatchIDs()...)
 		}
 	}
 
@@ -231,13 +332,55 @@ func (m *bracketPage) Update(msg tea.Msg) (*bracketPage, tea.Cmd) {
 }
 
 func (m *bracketPage) View() string {
+	// The spoilers may have been revealed or toggled from another page.
+	if m.spoilersVersion != m.spoilers.version {
+		m.refreshContent()
+	}
+
+	content := m.viewport.View()
+	if m.showMatchDetail {
+		content = lipgloss.Place(
+			m.width,
+			m.contentHeight(),
+			lipgloss.Center,
+			lipgloss.Center,
+			m.viewMatchDetail(),
+		)
+	}
+
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
-		m.viewport.View(),
+		content,
 		m.viewHelp(),
 	)
 }
 
+func (m *bracketPage) viewMatchDetail() string {
+	var (
+		slot  = m.cursor.selectedSlot()
+		match = m.spoilers.redactMatch(m.cursor.selectedMatch(), m.matches)
+		rows  = []string{m.styles.matchDetailTitle.Render(m.template.Rounds[slot.round].Title), ""}
+	)
+	for _, team := range match.Teams {
+		row := fmt.Sprintf("%-5s %s", team.Code, team.Name)
+		if team.Result != nil {
+			row += fmt.Sprintf("  %d", team.Result.GameWins)
+		}
+		if teamHasWon(team) {
+			row = m.styles.winnerTeamName.Render(row)
+		}
+		rows = append(rows, row)
+	}
+	rows = append(rows, "", formatMatchStrategy(match.Strategy))
+
+	if next, ok := m.cursor.next(m.cursor.selected); ok {
+		nextRound := m.template.Rounds[m.cursor.slots[next].round].Title
+		rows = append(rows, "Winner plays in "+nextRound)
+	}
+
+	return m.styles.matchDetail.Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
 func (m *bracketPage) viewHelp() string {
 	return m.styles.help.Render(m.help.View(m))
 }
@@ -254,6 +397,8 @@ func (p *bracketPage) ShortHelp() []key.Binding {
 	return []key.Binding{
 		p.keyMap.Right,
 		p.keyMap.Left,
+		p.keyMap.Select,
+		p.keyMap.Reveal,
 		p.keyMap.Previous,
 		p.keyMap.Quit,
 		p.keyMap.ShowFullHelp,
@@ -270,6 +415,13 @@ func (p *bracketPage) FullHelp() [][]key.Binding {
 			p.keyMap.Left,
 			p.keyMap.Previous,
 		},
+		// Match
+		{
+			p.keyMap.Select,
+			p.keyMap.Reveal,
+			p.keyMap.RevealRound,
+			p.keyMap.ToggleSpoilers,
+		},
 		// Navigation
 		{
 			p.keyMap.NextPage,
@@ -290,10 +442,64 @@ func (m *bracketPage) toggleFullHelp() {
 }
 
 func (m *bracketPage) initViewport() {
-	content := renderBracket(m.template, m.matches, m.width, m.contentHeight(), m.styles)
 	m.viewport = viewport.New(m.width, m.contentHeight())
-	m.viewport.SetContent(content)
 	m.viewport.SetHorizontalStep(5)
+	// The arrows move the cursor between the matches instead,
+	// the viewport follows the selected match.
+	m.viewport.KeyMap.Up.SetEnabled(false)
+	m.viewport.KeyMap.Down.SetEnabled(false)
+	m.viewport.KeyMap.Left.SetEnabled(false)
+	m.viewport.KeyMap.Right.SetEnabled(false)
+	m.xOffset = 0
+
+	m.refreshContent()
+}
+
+// refreshContent renders the bracket with the selected match highlighted
+// and scrolls the viewport to keep the selected match visible.
+func (m *bracketPage) refreshContent() {
+	content := renderBracket(
+		m.template,
+		m.matches,
+		m.cursor.highlights(),
+		m.spoilers,
+		m.width,
+		m.contentHeight(),
+		m.styles,
+	)
+	m.viewport.SetContent(content)
+	m.spoilersVersion = m.spoilers.version
+
+	if !m.cursor.isEmpty() {
+		m.scrollToSlot(m.cursor.selectedSlot(), lipgloss.Width(content))
+	}
+}
+
+// selectedRoundMatchIDs returns the IDs of the matches of the round of the selected match.
+func (m *bracketPage) selectedRoundMatchIDs() []string {
+	var ids []string
+	for _, i := range m.cursor.roundSlots(m.cursor.selectedSlot().round) {
+		ids = append(ids, m.matches[m.cursor.slots[i].match].ID)
+	}
+	return ids
+}
+
+func (m *bracketPage) scrollToSlot(slot bracketSlot, contentWidth int) {
+	top, bottom := slot.top, slot.top+bracketMatchHeight
+	if top < m.viewport.YOffset {
+		m.viewport.SetYOffset(top)
+	} else if bottom > m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(bottom - m.viewport.Height)
+	}
+
+	left, right := slot.left, slot.left+matchWidth
+	if left < m.xOffset {
+		m.xOffset = left
+	} else if right > m.xOffset+m.viewport.Width {
+		m.xOffset = right - m.viewport.Width
+	}
+	m.xOffset = max(min(m.xOffset, contentWidth-m.viewport.Width), 0)
+	m.viewport.SetXOffset(m.xOffset)
 }
 
 func (m *bracketPage) contentHeight() int {
@@ -308,8 +514,21 @@ func (m *bracketPage) helpHeight() int {
 	return bracketPageShortHelpHeight + padding
 }
 
-func drawMatch(match lolesports.Match, width int, styles bracketPageStyles) string {
-	borderWidth := styles.match.GetHorizontalBorderSize()
+func drawMatch(
+	match lolesports.Match,
+	width int,
+	highlight matchHighlight,
+	styles bracketPageStyles,
+) string {
+	matchStyle := styles.match
+	switch highlight {
+	case matchHighlightSelected:
+		matchStyle = styles.selectedMatch
+	case matchHighlightPath:
+		matchStyle = styles.pathMatch
+	}
+
+	borderWidth := matchStyle.GetHorizontalBorderSize()
 	rowWidth := width - borderWidth
 	if rowWidth <= 0 {
 		return ""
@@ -360,18 +579,53 @@ func drawMatch(match lolesports.Match, width int, styles bracketPageStyles) stri
 		rowStyle.Render(team2Row),
 	)
 
-	return styles.match.Render(content)
+	return matchStyle.Render(content)
 }
 
 func drawLinks(links []rift.Link, styles bracketPageStyles) string {
-	var linksView string
+	var (
+		linksView string
+		// Rows of the horizontal lines joined by the vertical line of an L-shaped link.
+		junctions = make(map[int]string)
+	)
 
 	for _, link := range links {
 		linksView += strings.Repeat("\n", link.Above)
-		linksView += styles.link.Render(drawLink(link))
+
+		row := strings.Count(linksView, "\n")
+		switch link.Type {
+		case rift.LinkTypeLDown:
+			junctions[row+link.Height+1] = upJunction
+		case rift.LinkTypeLUp:
+			junctions[row-1] = downJunction
+		}
+
+		linksView += drawLink(link)
+	}
+
+	// The rows are rendered one by one, otherwise the shorter ones
+	// would be padded and shift the links.
+	rows := strings.Split(linksView, "\n")
+	for i, row := range rows {
+		if junction, ok := junctions[i]; ok {
+			row = joinVerticalLine(row, junction)
+		}
+		if row != "" {
+			rows[i] = styles.link.Render(row)
+		}
 	}
 
-	return linksView
+	return strings.Join(rows, "\n")
+}
+
+// joinVerticalLine replaces the horizontal line crossed by the vertical line
+// of the links in row with junction.
+func joinVerticalLine(row, junction string) string {
+	runes := []rune(row)
+	if len(runes) < 2 || string(runes[1]) != horizontalLine {
+		return row
+	}
+	return string(runes[0]) + junction + string(runes[2:])
 }
 
 func drawLink(link rift.Link) string {
@@ -393,11 +647,45 @@ func drawLink(link rift.Link) string {
 		sb.WriteString(strings.Repeat(" "+verticalLine+"\n", link.Height))
 		sb.WriteString(horizontalLine + bottomRightCorner + " ")
 
+	// Joins the vertical line of the link below, e.g. when three matches
+	// lead to the same one.
+	//
+	// ─┐
+	// │
+	case rift.LinkTypeLDown:
+		sb.WriteString(horizontalLine + topRightCorner + "\n")
+		sb.WriteString(strings.Repeat(" "+verticalLine+"\n", link.Height))
+
+	// Joins the vertical line of the link above.
+	//
+	// │
+	// ─┘
+	case rift.LinkTypeLUp:
+		sb.WriteString(strings.Repeat(" "+verticalLine+"\n", link.Height))
+		sb.WriteString(horizontalLine + bottomRightCorner + " ")
+
 	// ───
 	case rift.LinkTypeHorizontal:
 		sb.WriteString(strings.Repeat(horizontalLine, linkWidth))
 
-	// loser-advance, reseed, etc.
+	// ─R─
+	case rift.LinkTypeReseed:
+		sb.WriteString(horizontalLine + reseedMarker + horizontalLine)
+
+	// ╌┐
+	// ╎
+	// └╌
+	//
+	// Drawn as a straight dashed line if the matches are on the same row.
+	case rift.LinkTypeLoserAdvance:
+		if link.Height == 0 {
+			sb.WriteString(strings.Repeat(dashedHorizontalLine, linkWidth))
+			break
+		}
+		sb.WriteString(dashedHorizontalLine + topRightCorner + "\n")
+		sb.WriteString(strings.Repeat(" "+dashedVerticalLine+"\n", link.Height))
+		sb.WriteString(" " + bottomLeftCorner + dashedHorizontalLine + "\n")
+
 	default:
 		sb.WriteString(strings.Repeat(" ", linkWidth))
 	}
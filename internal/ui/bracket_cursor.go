@@ -0,0 +1,268 @@
+package ui
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/matthieugusmini/go-lolesports"
+
+	"github.com/matthieugusmini/rift/internal/rift"
+)
+
+// Height of a match drawn in the bracket, borders included.
+const bracketMatchHeight = 5
+
+type matchHighlight int
+
+const (
+	matchHighlightNone matchHighlight = iota
+	// The match is on the path of the selected match to the final.
+	matchHighlightPath
+	matchHighlightSelected
+)
+
+// bracketSlot is the position of a match in the rendered bracket,
+// before the bracket is centered.
+type bracketSlot struct {
+	// Index of the round of the template.
+	round int
+	// Index of the match in the matches of the stage.
+	match int
+	// Row and column of the top-left corner of the match.
+	top, left int
+}
+
+func (s bracketSlot) center() int { return s.top + bracketMatchHeight/2 }
+
+// layOutBracket returns the position of every match of the bracket
+// as drawn by renderBracket, in the order of the matches of the stage.
+func layOutBracket(tmpl rift.BracketTemplate) []bracketSlot {
+	var (
+		slots      []bracketSlot
+		left       int
+		matchIndex int
+	)
+	for roundIndex, round := range tmpl.Rounds {
+		if len(round.Links) > 0 {
+			left += lipgloss.Width(drawLinks(round.Links, bracketPageStyles{}))
+		}
+
+		// Title of the round and the empty line below it.
+		top := 2
+		for _, match := range round.Matches {
+			top += match.Above
+
+			switch match.DisplayType {
+			case rift.DisplayTypeMatch:
+				slots = append(slots, bracketSlot{
+					round: roundIndex,
+					match: matchIndex,
+					top:   top,
+					left:  left,
+				})
+				matchIndex++
+				top += bracketMatchHeight
+			case rift.DisplayTypeHorizontalLine:
+				top++
+			}
+
+			// Empty line between two matches.
+			top++
+		}
+
+		left += matchWidth
+	}
+
+	return slots
+}
+
+// bracketCursor moves the selection between the matches of a bracket
+// following the previous matches of each match when known, or the position
+// of the matches otherwise.
+type bracketCursor struct {
+	slots    []bracketSlot
+	matches  []lolesports.Match
+	selected int
+}
+
+func newBracketCursor(tmpl rift.BracketTemplate, matches []lolesports.Match) bracketCursor {
+	slots := layOutBracket(tmpl)
+
+	// Start on the first match to play, or on the final if all of them have been played.
+	selected := len(slots) - 1
+	for i, slot := range slots {
+		if slot.match < len(matches) && !isMatchPlayed(matches[slot.match]) {
+			selected = i
+			break
+		}
+	}
+
+	return bracketCursor{
+		slots:    slots,
+		matches:  matches,
+		selected: max(selected, 0),
+	}
+}
+
+func (c bracketCursor) isEmpty() bool { return len(c.slots) == 0 }
+
+func (c bracketCursor) selectedSlot() bracketSlot { return c.slots[c.selected] }
+
+func (c bracketCursor) selectedMatch() lolesports.Match {
+	return c.matches[c.selectedSlot().match]
+}
+
+// moveToNextRound selects the match played by the winner of the selected match.
+func (c *bracketCursor) moveToNextRound() {
+	if next, ok := c.next(c.selected); ok {
+		c.selected = next
+	}
+}
+
+// moveToPreviousRound selects the closest of the previous matches of the selected match.
+func (c *bracketCursor) moveToPreviousRound() {
+	current := c.selectedSlot()
+
+	var candidates []int
+	for i, slot := range c.slots {
+		if c.isPreviousMatch(slot, current) {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = c.roundSlots(current.round - 1)
+	}
+
+	if prev, ok := c.closest(candidates, current.center()); ok {
+		c.selected = prev
+	}
+}
+
+// moveToSibling selects the match above (offset < 0) or below (offset > 0)
+// the selected match in the same round.
+func (c *bracketCursor) moveToSibling(offset int) {
+	siblings := c.roundSlots(c.selectedSlot().round)
+	i := slices.Index(siblings, c.selected) + offset
+	if i >= 0 && i < len(siblings) {
+		c.selected = siblings[i]
+	}
+}
+
+// pathToFinal returns the slots of the matches the winner of the selected match
+// would play until the final, the selected match excluded.
+func (c bracketCursor) pathToFinal() []int {
+	var (
+		path    []int
+		visited = map[int]bool{c.selected: true}
+	)
+	for i, ok := c.next(c.selected); ok && !visited[i]; i, ok = c.next(i) {
+		visited[i] = true
+		path = append(path, i)
+	}
+	return path
+}
+
+// highlights returns how each match of the stage must be highlighted.
+func (c bracketCursor) highlights() map[int]matchHighlight {
+	if c.isEmpty() {
+		return nil
+	}
+
+	highlights := map[int]matchHighlight{
+		c.selectedSlot().match: matchHighlightSelected,
+	}
+	for _, i := range c.pathToFinal() {
+		highlights[c.slots[i].match] = matchHighlightPath
+	}
+	return highlights
+}
+
+// next returns the slot of the match played by the winner of the match of the i-th slot.
+//
+// The winner stays in the bracket of the match while the loser of an upper bracket match
+// drops into the lower bracket, see isLowerBracket, whatever the order of the next matches.
+// Without previous matches, the closest match of the next round is returned.
+func (c bracketCursor) next(i int) (int, bool) {
+	nexts := c.nextSlots(i)
+	if len(nexts) > 0 {
+		j := slices.IndexFunc(nexts, func(j int) bool { return !c.isLowerBracket(j) })
+		return nexts[max(j, 0)], true
+	}
+
+	if c.hasPreviousMatches() {
+		return 0, false
+	}
+	current := c.slots[i]
+	return c.closest(c.roundSlots(current.round+1), current.center())
+}
+
+// nextSlots returns the slots of the matches whose previous matches include
+// the match of the i-th slot.
+func (c bracketCursor) nextSlots(i int) []int {
+	var nexts []int
+	for j, slot := range c.slots {
+		if c.isPreviousMatch(c.slots[i], slot) {
+			nexts = append(nexts, j)
+		}
+	}
+	return nexts
+}
+
+// isLowerBracket returns true if the match of the i-th slot is part of the lower bracket
+// of a double elimination stage, i.e. its loser is eliminated while the teams go on
+// to a single next match. The losers of the upper bracket go on to the lower bracket
+// and the grand final has no next match.
+func (c bracketCursor) isLowerBracket(i int) bool {
+	return len(c.nextSlots(i)) == 1
+}
+
+// isPreviousMatch returns true if the match of prev is one of the previous matches
+// of the match of slot.
+func (c bracketCursor) isPreviousMatch(prev, slot bracketSlot) bool {
+	if prev.match >= len(c.matches) || slot.match >= len(c.matches) {
+		return false
+	}
+	return slices.Contains(c.matches[slot.match].PreviousMatchIDs, c.matches[prev.match].ID)
+}
+
+func (c bracketCursor) hasPreviousMatches() bool {
+	for _, slot := range c.slots {
+		if slot.match < len(c.matches) && len(c.matches[slot.match].PreviousMatchIDs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// roundSlots returns the slots of the matches of the given round from top to bottom.
+func (c bracketCursor) roundSlots(round int) []int {
+	var slots []int
+	for i, slot := range c.slots {
+		if slot.round == round {
+			slots = append(slots, i)
+		}
+	}
+	return slots
+}
+
+// closest returns the slot among candidates whose center is the closest to the given row.
+func (c bracketCursor) closest(candidates []int, row int) (int, bool) {
+	if len(candidates) == 0 {
+		return 0, false
+	}
+
+	return slices.MinFunc(candidates, func(a, b int) int {
+		return abs(c.slots[a].center()-row) - abs(c.slots[b].center()-row)
+	}), true
+}
+
+func isMatchPlayed(match lolesports.Match) bool {
+	return slices.ContainsFunc(match.Teams, teamHasWon)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
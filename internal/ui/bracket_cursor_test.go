@@ -0,0 +1,145 @@
+package ui_test
+
+import (
+	"testing"
+
+	"github.com/matthieugusmini/go-lolesports"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matthieugusmini/rift/internal/rift"
+	"github.com/matthieugusmini/rift/internal/ui"
+)
+
+func TestBracketCursor(t *testing.T) {
+	// Quarterfinals 1 to 4, semifinals 5 and 6 then final 7.
+	linkedMatches := []lolesports.Match{
+		newPlayedTestMatch("1"),
+		newPlayedTestMatch("2"),
+		{ID: "3"},
+		{ID: "4"},
+		{ID: "5", PreviousMatchIDs: []string{"1", "2"}},
+		{ID: "6", PreviousMatchIDs: []string{"3", "4"}},
+		{ID: "7", PreviousMatchIDs: []string{"5", "6"}},
+	}
+	unlinkedMatches := make([]lolesports.Match, len(linkedMatches))
+	for i, match := range linkedMatches {
+		match.PreviousMatchIDs = nil
+		unlinkedMatches[i] = match
+	}
+
+	tests := []struct {
+		name         string
+		matches      []lolesports.Match
+		motions      string
+		wantSelected string
+		wantPath     []string
+	}{
+		{
+			name:         "starts on first match to play",
+			matches:      linkedMatches,
+			wantSelected: "3",
+			wantPath:     []string{"6", "7"},
+		},
+		{
+			name:         "moves to siblings",
+			matches:      linkedMatches,
+			motions:      "kkkj",
+			wantSelected: "2",
+			wantPath:     []string{"5", "7"},
+		},
+		{
+			name:         "moves to next rounds",
+			matches:      linkedMatches,
+			motions:      "lll",
+			wantSelected: "7",
+		},
+		{
+			name:         "moves to previous rounds",
+			matches:      linkedMatches,
+			motions:      "jlhh",
+			wantSelected: "3",
+			wantPath:     []string{"6", "7"},
+		},
+		{
+			name:         "follows positions without previous matches",
+			matches:      unlinkedMatches,
+			motions:      "jl",
+			wantSelected: "6",
+			wantPath:     []string{"7"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := rift.GenerateBracketTemplate(tt.matches)
+
+			gotSelected, gotPath := ui.MoveBracketCursor(tmpl, tt.matches, tt.motions)
+
+			assert.Equal(t, tt.wantSelected, gotSelected)
+			assert.Equal(t, tt.wantPath, gotPath)
+		})
+	}
+}
+
+func TestBracketCursor_DoubleElimination(t *testing.T) {
+	// Upper semifinals 1 and 2, lower round 3 listed before the upper final 4,
+	// lower final 5 then grand final 6.
+	matches := []lolesports.Match{
+		newPlayedTestMatch("1"),
+		newPlayedTestMatch("2"),
+		{ID: "3", PreviousMatchIDs: []string{"1", "2"}},
+		{ID: "4", PreviousMatchIDs: []string{"1", "2"}},
+		{ID: "5", PreviousMatchIDs: []string{"3", "4"}},
+		{ID: "6", PreviousMatchIDs: []string{"4", "5"}},
+	}
+	matchSlot := rift.Match{DisplayType: rift.DisplayTypeMatch}
+	tmpl := rift.BracketTemplate{Rounds: []rift.Round{
+		{Matches: []rift.Match{matchSlot, matchSlot}},
+		{Matches: []rift.Match{matchSlot, matchSlot}},
+		{Matches: []rift.Match{matchSlot}},
+		{Matches: []rift.Match{matchSlot}},
+	}}
+
+	tests := []struct {
+		name         string
+		motions      string
+		wantSelected string
+		wantPath     []string
+	}{
+		{
+			name:         "follows winner in upper bracket",
+			motions:      "h",
+			wantSelected: "1",
+			wantPath:     []string{"4", "6"},
+		},
+		{
+			name:         "moves to match of winner",
+			motions:      "hl",
+			wantSelected: "4",
+			wantPath:     []string{"6"},
+		},
+		{
+			name:         "follows winner in lower bracket",
+			wantSelected: "3",
+			wantPath:     []string{"5", "6"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSelected, gotPath := ui.MoveBracketCursor(tmpl, matches, tt.motions)
+
+			assert.Equal(t, tt.wantSelected, gotSelected)
+			assert.Equal(t, tt.wantPath, gotPath)
+		})
+	}
+}
+
+func newPlayedTestMatch(id string) lolesports.Match {
+	win, loss := "win", "loss"
+	return lolesports.Match{
+		ID: id,
+		Teams: []lolesports.Team{
+			{Code: "T1", Result: &lolesports.Result{Outcome: &win, GameWins: 3}},
+			{Code: "GEN", Result: &lolesports.Result{Outcome: &loss, GameWins: 1}},
+		},
+	}
+}
@@ -0,0 +1,127 @@
+package ui_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/matthieugusmini/go-lolesports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matthieugusmini/rift/internal/rift"
+	"github.com/matthieugusmini/rift/internal/ui"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestRenderBracket_Links(t *testing.T) {
+	// The matches of the first round are centered on the rows 4, 10 and 16.
+	tests := []struct {
+		linkType rift.LinkType
+		feeders  int
+		links    []rift.Link
+		// Number of newlines above the match of the second round.
+		above int
+	}{
+		{
+			linkType: rift.LinkTypeHorizontal,
+			feeders:  1,
+			links:    []rift.Link{{Type: rift.LinkTypeHorizontal, Above: 4}},
+		},
+		{
+			linkType: rift.LinkTypeZDown,
+			feeders:  2,
+			links:    []rift.Link{{Type: rift.LinkTypeZDown, Height: 1, Above: 4}},
+			above:    3,
+		},
+		{
+			linkType: rift.LinkTypeZUp,
+			feeders:  2,
+			links:    []rift.Link{{Type: rift.LinkTypeZUp, Height: 1, Above: 8}},
+			above:    3,
+		},
+		{
+			linkType: rift.LinkTypeLDown,
+			feeders:  3,
+			links: []rift.Link{
+				{Type: rift.LinkTypeLDown, Height: 5, Above: 4},
+				{Type: rift.LinkTypeHorizontal},
+			},
+			above: 6,
+		},
+		{
+			linkType: rift.LinkTypeLUp,
+			feeders:  3,
+			links: []rift.Link{
+				{Type: rift.LinkTypeHorizontal, Above: 10},
+				{Type: rift.LinkTypeLUp, Height: 5, Above: 1},
+			},
+			above: 6,
+		},
+		{
+			linkType: rift.LinkTypeReseed,
+			feeders:  1,
+			links:    []rift.Link{{Type: rift.LinkTypeReseed, Above: 4}},
+		},
+		{
+			linkType: rift.LinkTypeLoserAdvance,
+			feeders:  1,
+			links:    []rift.Link{{Type: rift.LinkTypeLoserAdvance, Height: 3, Above: 4}},
+			above:    4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.linkType), func(t *testing.T) {
+			var (
+				firstRound = rift.Round{Title: "Round 1"}
+				matches    []lolesports.Match
+			)
+			for i := range tt.feeders {
+				firstRound.Matches = append(
+					firstRound.Matches,
+					rift.Match{DisplayType: rift.DisplayTypeMatch},
+				)
+				matches = append(
+					matches,
+					newTestMatch(fmt.Sprintf("T%dA", i+1), fmt.Sprintf("T%dB", i+1)),
+				)
+			}
+			secondRound := rift.Round{
+				Title:   "Round 2",
+				Links:   tt.links,
+				Matches: []rift.Match{{DisplayType: rift.DisplayTypeMatch, Above: tt.above}},
+			}
+			matches = append(matches, newTestMatch("TBD", "TBD"))
+			tmpl := rift.BracketTemplate{Rounds: []rift.Round{firstRound, secondRound}}
+
+			got := ansi.Strip(ui.RenderBracket(tmpl, matches, 0))
+
+			assertGolden(t, filepath.Join("testdata", "links", string(tt.linkType)+".golden"), got)
+		})
+	}
+}
+
+func newTestMatch(team1, team2 string) lolesports.Match {
+	return lolesports.Match{
+		Teams: []lolesports.Team{{Code: team1}, {Code: team2}},
+	}
+}
+
+// assertGolden compares got to the content of the golden file at path,
+// or overwrites the golden file with got if the -update flag is set.
+func assertGolden(t *testing.T, path, got string) {
+	t.Helper()
+
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), got)
+}
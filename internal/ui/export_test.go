@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/matthieugusmini/go-lolesports"
+
+	"github.com/matthieugusmini/rift/internal/rift"
+)
+
+// MoveBracketCursor moves the cursor of a bracket with the given vim motions (h, j, k, l)
+// and returns the ID of the selected match and of the matches on its path to the final.
+func MoveBracketCursor(
+	tmpl rift.BracketTemplate,
+	matches []lolesports.Match,
+	motions string,
+) (string, []string) {
+	c := newBracketCursor(tmpl, matches)
+	for _, motion := range motions {
+		switch motion {
+		case 'h':
+			c.moveToPreviousRound()
+		case 'j':
+			c.moveToSibling(1)
+		case 'k':
+			c.moveToSibling(-1)
+		case 'l':
+			c.moveToNextRound()
+		}
+	}
+
+	var path []string
+	for _, i := range c.pathToFinal() {
+		path = append(path, matches[c.slots[i].match].ID)
+	}
+	return c.selectedMatch().ID, path
+}
+
+type SpoilerGuard = spoilerGuard
+
+// NewSpoilerGuard returns a guard persisting the matches revealed in store if not nil.
+func NewSpoilerGuard(enabled bool, store RevealedMatchStore) *SpoilerGuard {
+	g := newSpoilerGuard(enabled, slog.New(slog.DiscardHandler))
+	g.store = store
+	return g
+}
+
+func (g *SpoilerGuard) SetRevealedUntilStore(store RevealedUntilStore) {
+	g.setRevealedUntilStore(store)
+}
+
+func (g *SpoilerGuard) IsHidden(matchID string) bool { return g.isHidden(matchID) }
+
+func (g *SpoilerGuard) Reveal(matchIDs ...string) { g.reveal(matchIDs...) }
+
+func (g *SpoilerGuard) RevealUntil(t time.Time) { g.revealUntil(t) }
+
+func (g *SpoilerGuard) Observe(events []lolesports.Event) { g.observe(events) }
+
+func (g *SpoilerGuard) Toggle() { g.toggle() }
+
+func (g *SpoilerGuard) RedactMatch(
+	match lolesports.Match,
+	stageMatches []lolesports.Match,
+) lolesports.Match {
+	return g.redactMatch(match, stageMatches)
+}
+
+func (g *SpoilerGuard) HidesStage(stage lolesports.Stage) bool { return g.hidesStage(stage) }
+
+// ObserveStages records the start time of the matches of the stages found
+// in the schedule of the league.
+func (g *SpoilerGuard) ObserveStages(
+	loader LoLEsportsLoader,
+	leagueID string,
+	stages []lolesports.Stage,
+) {
+	matchIDs := g.unobservedMatchIDs(stages)
+	logger := slog.New(slog.DiscardHandler)
+	g.observe(fetchMatchEvents(context.Background(), loader, leagueID, matchIDs, logger))
+}
+
+// SchedulePageMatchIDs returns the IDs of the matches displayed by the schedule
+// after displaying the cached page, revalidating it with the fresh page
+// then fetching the next page.
+func SchedulePageMatchIDs(cached, fresh, next []lolesports.Event) []string {
+	logger := slog.New(slog.DiscardHandler)
+	p := newSchedulePage(nil, newSpoilerGuard(true, logger), logger)
+	p.setSize(80, 40)
+
+	p.Update(loadedCachedEventsMessage{events: cached, nextPageToken: "cached"})
+	p.Update(fetchedEventsMessage{
+		events:        fresh,
+		pageDirection: pageDirectionInitial,
+		nextPageToken: "fresh",
+	})
+	p.Update(fetchedEventsMessage{events: next, pageDirection: pageDirectionNext})
+
+	var ids []string
+	for _, item := range p.matchList.Items() {
+		ids = append(ids, item.(matchItem).id)
+	}
+	return ids
+}
+
+// SchedulePageSelectedMatchID returns the ID of the match selected by the schedule
+// after selecting the match in the cached page filtered by filter, then revalidating
+// it with the fresh page.
+func SchedulePageSelectedMatchID(cached, fresh []lolesports.Event, filter, matchID string) string {
+	logger := slog.New(slog.DiscardHandler)
+	p := newSchedulePage(nil, newSpoilerGuard(true, logger), logger)
+	p.setSize(80, 40)
+
+	p.Update(loadedCachedEventsMessage{events: cached})
+	p.matchList.SetFilterText(filter)
+	p.matchList.Select(slices.IndexFunc(p.matchList.VisibleItems(), func(item list.Item) bool {
+		return item.(matchItem).id == matchID
+	}))
+	p.Update(fetchedEventsMessage{events: fresh, pageDirection: pageDirectionInitial})
+
+	item, ok := p.matchList.SelectedItem().(matchItem)
+	if !ok {
+		return ""
+	}
+	return item.id
+}
@@ -4,11 +4,12 @@ import "github.com/charmbracelet/bubbles/key"
 
 // These key bindings are available across all the application.
 type baseKeyMap struct {
-	NextPage      key.Binding
-	PrevPage      key.Binding
-	ShowFullHelp  key.Binding
-	CloseFullHelp key.Binding
-	Quit          key.Binding
+	NextPage       key.Binding
+	PrevPage       key.Binding
+	ShowFullHelp   key.Binding
+	CloseFullHelp  key.Binding
+	ToggleSpoilers key.Binding
+	Quit           key.Binding
 }
 
 func newBaseKeyMap() baseKeyMap {
@@ -29,6 +30,10 @@ func newBaseKeyMap() baseKeyMap {
 			key.WithKeys("?"),
 			key.WithHelp("?", "close help"),
 		),
+		ToggleSpoilers: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "toggle spoilers"),
+		),
 		Quit: key.NewBinding(
 			key.WithKeys("q"),
 			key.WithHelp("q", "quit"),
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
@@ -39,6 +40,7 @@ func newTeam(t lolesports.Team) team {
 }
 
 type matchItem struct {
+	id         string
 	team1      team
 	team2      team
 	startTime  time.Time
@@ -47,12 +49,12 @@ type matchItem struct {
 	strategy   string
 	flags      string
 
-	isCompleted          bool
-	spoilerBlockRevealed bool
+	isCompleted bool
 }
 
 func newMatchItem(event lolesports.Event) matchItem {
 	return matchItem{
+		id:          event.Match.ID,
 		team1:       newTeam(event.Match.Teams[0]),
 		team2:       newTeam(event.Match.Teams[1]),
 		startTime:   event.StartTime.Local(),
@@ -72,7 +74,11 @@ func (i matchItem) FilterValue() string {
 	}, "_")
 }
 
-func newMatchListItems(events []lolesports.Event) []list.Item {
+// newMatchListItems returns the items of the events, restoring first
+// the spoilers revealed in a previous session.
+func newMatchListItems(events []lolesports.Event, spoilers *spoilerGuard) []list.Item {
+	spoilers.observe(events)
+
 	items := make([]list.Item, len(events))
 
 	for i, event := range events {
@@ -82,10 +88,15 @@ func newMatchListItems(events []lolesports.Event) []list.Item {
 	return items
 }
 
-func newMatchList(events []lolesports.Event, width, height int) list.Model {
-	items := newMatchListItems(events)
+func newMatchList(
+	events []lolesports.Event,
+	spoilers *spoilerGuard,
+	keyMap schedulePageKeyMap,
+	width, height int,
+) list.Model {
+	items := newMatchListItems(events, spoilers)
 
-	l := list.New(items, newMatchItemDelegate(), width, height)
+	l := list.New(items, newMatchItemDelegate(spoilers, keyMap), width, height)
 	l.SetShowPagination(false)
 	l.SetShowStatusBar(false)
 	l.StatusMessageLifetime = time.Second * 2
@@ -186,12 +197,16 @@ func newDefaultMatchItemStyles() (s matchItemStyles) {
 }
 
 type matchItemDelegate struct {
-	styles matchItemStyles
+	spoilers *spoilerGuard
+	keyMap   schedulePageKeyMap
+	styles   matchItemStyles
 }
 
-func newMatchItemDelegate() matchItemDelegate {
+func newMatchItemDelegate(spoilers *spoilerGuard, keyMap schedulePageKeyMap) matchItemDelegate {
 	return matchItemDelegate{
-		styles: newDefaultMatchItemStyles(),
+		spoilers: spoilers,
+		keyMap:   keyMap,
+		styles:   newDefaultMatchItemStyles(),
 	}
 }
 
@@ -211,7 +226,7 @@ func (d matchItemDelegate) Render(w io.Writer, m list.Model, index int, item lis
 	// with their score.
 	if !matchItem.isCompleted && matchItem.startTime.After(time.Now()) {
 		title = d.viewTitleWithStartTime(matchItem, itemWidth)
-	} else if !matchItem.spoilerBlockRevealed {
+	} else if d.spoilers.isHidden(matchItem.id) {
 		title = d.viewTitleWithScoreSpoilerBlock(matchItem, itemWidth)
 	} else {
 		title = d.viewTitleWithScore(matchItem, itemWidth)
@@ -239,18 +254,52 @@ func (d matchItemDelegate) Spacing() int { return 0 }
 func (d matchItemDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
+		switch {
+		case key.Matches(msg, d.keyMap.RevealSpoiler):
 			d.revealSpoiler(m)
+		case key.Matches(msg, d.keyMap.RevealBlock):
+			d.revealBlock(m)
+		case key.Matches(msg, d.keyMap.RevealUntil):
+			d.revealUntilSelected(m)
 		}
 	}
 	return nil
 }
 
 func (d matchItemDelegate) revealSpoiler(m *list.Model) {
-	item := m.SelectedItem().(matchItem)
-	item.spoilerBlockRevealed = true
-	m.SetItem(m.Index(), item)
+	item, ok := m.SelectedItem().(matchItem)
+	if !ok {
+		return
+	}
+	d.spoilers.reveal(item.id)
+}
+
+// revealBlock reveals the matches of the same block of the same league
+// as the selected match, e.g. all the matches of a week.
+func (d matchItemDelegate) revealBlock(m *list.Model) {
+	selected, ok := m.SelectedItem().(matchItem)
+	if !ok {
+		return
+	}
+
+	var ids []string
+	for _, item := range m.Items() {
+		item, ok := item.(matchItem)
+		if ok && item.leagueName == selected.leagueName && item.blockName == selected.blockName {
+			ids = append(ids, item.id)
+		}
+	}
+	d.spoilers.reveal(ids...)
+}
+
+// revealUntilSelected reveals all the matches started at or before the selected match,
+// e.g. to catch up with the VODs watched so far.
+func (d matchItemDelegate) revealUntilSelected(m *list.Model) {
+	item, ok := m.SelectedItem().(matchItem)
+	if !ok {
+		return
+	}
+	d.spoilers.revealUntil(item.startTime)
 }
 
 //	┌──────┬────────────────────────────────────────┬──────┐
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/matthieugusmini/go-lolesports"
 
 	"github.com/matthieugusmini/rift/internal/rift"
+	"github.com/matthieugusmini/rift/internal/timeutil"
 )
 
 const (
@@ -46,6 +48,8 @@ type modelStyles struct {
 	normalNavItem   lipgloss.Style
 	selectedNavItem lipgloss.Style
 	separator       lipgloss.Style
+	offlineBadge    lipgloss.Style
+	staleSince      lipgloss.Style
 }
 
 func newDefaultModelStyles() (s modelStyles) {
@@ -66,6 +70,17 @@ func newDefaultModelStyles() (s modelStyles) {
 		Foreground(textSecondaryColor).
 		Bold(true)
 
+	s.offlineBadge = lipgloss.NewStyle().
+		Padding(0, 1).
+		Foreground(lipgloss.Color(white)).
+		Background(red).
+		Bold(true)
+
+	s.staleSince = lipgloss.NewStyle().
+		Padding(0, 1).
+		Foreground(textSecondaryColor).
+		Italic(true)
+
 	return s
 }
 
@@ -81,6 +96,10 @@ type LoLEsportsLoader interface {
 		opts *lolesports.GetScheduleOptions,
 	) (lolesports.Schedule, error)
 
+	// GetCachedSchedule returns the last schedule page fetched with the same options
+	// if any, without performing any network I/O.
+	GetCachedSchedule(opts *lolesports.GetScheduleOptions) (lolesports.Schedule, bool)
+
 	// LoadStandingsByTournamentIDs loads the standings associated with
 	// each given tournament ids.
 	LoadStandingsByTournamentIDs(
@@ -91,6 +110,32 @@ type LoLEsportsLoader interface {
 	// LoadCurrentSeasonSplits loads and returns all the LoL Esports splits
 	// for the current season.
 	LoadCurrentSeasonSplits(ctx context.Context) ([]lolesports.Split, error)
+
+	// StaleSince returns the time at which the oldest outdated data served
+	// because fresh data could not be fetched was stored, if any.
+	StaleSince() (time.Time, bool)
+}
+
+// RevealedMatchStore persists the matches whose results have been revealed
+// so that they stay revealed across sessions.
+type RevealedMatchStore interface {
+	// Get should return whether the match has been revealed along with
+	// a boolean indicating whether the match was found in the store.
+	Get(matchID string) (bool, bool, error)
+
+	// Set should store whether the match has been revealed.
+	Set(matchID string, revealed bool) error
+}
+
+// RevealedUntilStore persists the time up to which the results of the matches
+// have been revealed so that it still applies across sessions.
+type RevealedUntilStore interface {
+	// Get should return the time stored under the key along with
+	// a boolean indicating whether the key was found in the store.
+	Get(key string) (time.Time, bool, error)
+
+	// Set should store the time under the key.
+	Set(key string, t time.Time) error
 }
 
 // BracketTemplateLoader loads bracket templates.
@@ -99,8 +144,18 @@ type BracketTemplateLoader interface {
 	// that have a bracket template associated with them.
 	ListAvailableStageIDs(ctx context.Context) ([]string, error)
 
-	// Load returns the [rift.BracketTemplate] associated with stageID.
-	Load(ctx context.Context, stageID string) (rift.BracketTemplate, error)
+	// Load returns the [rift.BracketTemplate] associated with stageID,
+	// or one generated from the matches of the stage if there is none,
+	// validated against the matches of the stage.
+	Load(
+		ctx context.Context,
+		stageID string,
+		matches []lolesports.Match,
+	) (rift.BracketTemplate, error)
+
+	// StaleSince returns the time at which the oldest outdated template served
+	// because it could not be fetched was stored, if any.
+	StaleSince() (time.Time, bool)
 }
 
 // page is similar to a tea.Model but with the added ability to set its size.
@@ -138,29 +193,88 @@ type Model struct {
 	currentPage page
 	pages       map[state]page
 
+	lolesportsLoader LoLEsportsLoader
+	bracketLoader    BracketTemplateLoader
+
+	// Indicates whether the application runs without network access.
+	offline bool
+
+	// Shared by the pages to hide the results of the matches.
+	spoilers *spoilerGuard
+
 	styles modelStyles
 }
 
+// ModelOption represents a functional option to customize a [Model].
+type ModelOption func(*Model)
+
+// WithOffline indicates whether the application runs without network access,
+// in which case an offline badge is always displayed in the navbar.
+func WithOffline(offline bool) ModelOption {
+	return func(m *Model) {
+		m.offline = offline
+	}
+}
+
+// WithSpoilerProtection indicates whether the results of the matches are hidden
+// until revealed, in the schedule as well as in the standings.
+//
+// The protection can be toggled at any time from the application.
+func WithSpoilerProtection(enabled bool) ModelOption {
+	return func(m *Model) {
+		m.spoilers.enabled = enabled
+	}
+}
+
+// WithRevealedMatchStore persists the matches revealed in the store
+// so that they are still revealed the next time the application starts.
+func WithRevealedMatchStore(store RevealedMatchStore) ModelOption {
+	return func(m *Model) {
+		m.spoilers.store = store
+	}
+}
+
+// WithRevealedUntilStore persists in the store the time up to which the results
+// are revealed so that the matches played until then are still revealed
+// the next time the application starts, including the ones never displayed.
+func WithRevealedUntilStore(store RevealedUntilStore) ModelOption {
+	return func(m *Model) {
+		m.spoilers.setRevealedUntilStore(store)
+	}
+}
+
 // NewModel returns a new [Model] initialized with all its sub-models
 // and default styles.
 func NewModel(
 	lolesportsLoader LoLEsportsLoader,
 	bracketLoader BracketTemplateLoader,
 	logger *slog.Logger,
+	opts ...ModelOption,
 ) Model {
-	schedulePage := newSchedulePage(lolesportsLoader, logger)
-	standingsPage := newStandingsPage(lolesportsLoader, bracketLoader, logger)
+	// Enabled by default as the results of the schedule have always been hidden.
+	spoilers := newSpoilerGuard(true, logger)
+	schedulePage := newSchedulePage(lolesportsLoader, spoilers, logger)
+	standingsPage := newStandingsPage(lolesportsLoader, bracketLoader, spoilers, logger)
 
 	pages := map[state]page{
 		stateShowSchedule:  schedulePage,
 		stateShowStandings: standingsPage,
 	}
 
-	return Model{
-		currentPage: schedulePage,
-		pages:       pages,
-		styles:      newDefaultModelStyles(),
+	m := Model{
+		currentPage:      schedulePage,
+		pages:            pages,
+		lolesportsLoader: lolesportsLoader,
+		bracketLoader:    bracketLoader,
+		spoilers:         spoilers,
+		styles:           newDefaultModelStyles(),
 	}
+
+	for _, opt := range opts {
+		opt(&m)
+	}
+
+	return m
 }
 
 // Init implements the [github.com/charmbracelet/bubbletea.Model] interface.
@@ -216,10 +330,12 @@ func (m Model) viewNavbar(
 	width int,
 ) string {
 	logo := m.styles.logo.Render(logo)
+	offlineBadge := m.viewOfflineBadge()
 
-	// Add a padding with the same size as the logo on the other end
-	// of the navbar to center the nav items.
-	padding := strings.Repeat(" ", lipgloss.Width(logo))
+	// Both ends of the navbar take the same width to center the nav items.
+	sideWidth := max(lipgloss.Width(logo), lipgloss.Width(offlineBadge))
+	logo = lipgloss.PlaceHorizontal(sideWidth, lipgloss.Left, logo)
+	offlineBadge = lipgloss.PlaceHorizontal(sideWidth, lipgloss.Right, offlineBadge)
 
 	styledNavItems := make([]string, len(navItems))
 	for i, navItem := range navItems {
@@ -231,19 +347,56 @@ func (m Model) viewNavbar(
 		}
 	}
 
-	availWidth := width - lipgloss.Width(logo) - lipgloss.Width(padding)
+	availWidth := width - 2*sideWidth
 	navItemsStyle := lipgloss.NewStyle().
 		Width(availWidth).
 		Align(lipgloss.Center)
 	renderedNavItems := navItemsStyle.Render(strings.Join(styledNavItems, separatorBullet))
 
-	navbar := logo + renderedNavItems + padding
+	navbar := logo + renderedNavItems + offlineBadge
 
 	separator := m.styles.separator.Render(strings.Repeat(separatorLine, width))
 
 	return fmt.Sprintf("%s\n%s", navbar, separator)
 }
 
+// viewOfflineBadge returns an empty string unless the application runs
+// without network access or the displayed data is outdated because it
+// could not be fetched.
+func (m Model) viewOfflineBadge() string {
+	staleSince, isStale := oldestStaleSince(m.lolesportsLoader, m.bracketLoader)
+	if !m.offline && !isStale {
+		return ""
+	}
+
+	badge := m.styles.offlineBadge.Render("OFFLINE")
+	if isStale {
+		badge += m.styles.staleSince.Render("since " + formatStaleSince(staleSince))
+	}
+	return badge
+}
+
+func oldestStaleSince(loaders ...interface{ StaleSince() (time.Time, bool) }) (time.Time, bool) {
+	var (
+		oldest  time.Time
+		isStale bool
+	)
+	for _, loader := range loaders {
+		staleSince, ok := loader.StaleSince()
+		if ok && (!isStale || staleSince.Before(oldest)) {
+			oldest, isStale = staleSince, true
+		}
+	}
+	return oldest, isStale
+}
+
+func formatStaleSince(t time.Time) string {
+	if timeutil.IsToday(t) {
+		return t.Format("15:04")
+	}
+	return t.Format("02 Jan")
+}
+
 func (m Model) navigateRight() (Model, tea.Cmd) {
 	m.selectedNavIndex = moveNavigationBarCursorRight(m.selectedNavIndex)
 	return m.updateCurrentPage()
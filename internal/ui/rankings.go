@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -13,7 +14,8 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
 	"github.com/matthieugusmini/go-lolesports"
-	"github.com/matthieugusmini/rift/internal/timeutil"
+
+	"github.com/matthieugusmini/rift/internal/rift"
 )
 
 const (
@@ -23,11 +25,15 @@ const (
 	rankingPageFullHelpHeight  = 3
 )
 
+// Displayed instead of the ranking and the record of the teams when they are hidden.
+const hiddenRankingValue = "?"
+
 type rankingPageKeyMap struct {
 	baseKeyMap
 
 	Up       key.Binding
 	Down     key.Binding
+	Reveal   key.Binding
 	Previous key.Binding
 }
 
@@ -42,6 +48,10 @@ func newDefaultRankingPageKeyMap() rankingPageKeyMap {
 			key.WithKeys("down", "j"),
 			key.WithHelp("↓/j", "down"),
 		),
+		Reveal: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "reveal spoilers"),
+		),
 		Previous: key.NewBinding(
 			key.WithKeys("esc"),
 			key.WithHelp("esc", "previous"),
@@ -113,28 +123,34 @@ type rankingPage struct {
 	stage         lolesports.Stage
 	split         lolesports.Split
 	league        lolesports.League
-	viewport      viewport.Model
-	help          help.Model
-	keyMap        rankingPageKeyMap
-	styles        rankingPageStyles
+	spoilers      *spoilerGuard
+	// Version of the spoilers the content was rendered with.
+	spoilersVersion int
+	viewport        viewport.Model
+	help            help.Model
+	keyMap          rankingPageKeyMap
+	styles          rankingPageStyles
 }
 
 func newRankingPage(
 	split lolesports.Split,
 	league lolesports.League,
 	stage lolesports.Stage,
+	spoilers *spoilerGuard,
 	width, height int,
 ) *rankingPage {
 	p := &rankingPage{
-		width:  width,
-		height: height,
-		split:  split,
-		league: league,
-		stage:  stage,
-		help:   help.New(),
-		keyMap: newDefaultRankingPageKeyMap(),
-		styles: newDefaultRankingPageStyles(),
+		width:    width,
+		height:   height,
+		split:    split,
+		league:   league,
+		stage:    stage,
+		spoilers: spoilers,
+		help:     help.New(),
+		keyMap:   newDefaultRankingPageKeyMap(),
+		styles:   newDefaultRankingPageStyles(),
 	}
+	spoilers.restore(stageMatchIDs(stage)...)
 
 	p.initViewport()
 
@@ -150,6 +166,9 @@ func (p *rankingPage) Update(msg tea.Msg) (*rankingPage, tea.Cmd) {
 		case key.Matches(msg, p.keyMap.ShowFullHelp),
 			key.Matches(msg, p.keyMap.CloseFullHelp):
 			p.toggleFullHelp()
+
+		case key.Matches(msg, p.keyMap.Reveal):
+			p.spoilers.revealStage(p.stage)
 		}
 	}
 
@@ -160,6 +179,11 @@ func (p *rankingPage) Update(msg tea.Msg) (*rankingPage, tea.Cmd) {
 }
 
 func (p *rankingPage) View() string {
+	// The spoilers may have been revealed or toggled from another page.
+	if p.spoilersVersion != p.spoilers.version {
+		p.refreshContent()
+	}
+
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
 		p.viewHeader(),
@@ -173,7 +197,7 @@ func (p *rankingPage) viewHeader() string {
 		fmt.Sprintf("%s: %s Standings", p.split.Name, p.league.Name),
 	)
 
-	tournamentState := computeTournamentState(p.split.StartTime, p.split.EndTime)
+	tournamentState := rift.ComputeTournamentState(p.split.StartTime, p.split.EndTime)
 	tournamentPeriod := formatTournamentPeriod(p.split.StartTime, p.split.EndTime)
 	tournamentType := p.split.Region
 	stageInfo := strings.Join(
@@ -198,6 +222,7 @@ func (p *rankingPage) ShortHelp() []key.Binding {
 	return []key.Binding{
 		p.keyMap.Up,
 		p.keyMap.Down,
+		p.keyMap.Reveal,
 		p.keyMap.Previous,
 		p.keyMap.Quit,
 		p.keyMap.ShowFullHelp,
@@ -217,6 +242,11 @@ func (p *rankingPage) FullHelp() [][]key.Binding {
 			p.keyMap.NextPage,
 			p.keyMap.PrevPage,
 		},
+		// Spoilers
+		{
+			p.keyMap.Reveal,
+			p.keyMap.ToggleSpoilers,
+		},
 		// Others
 		{
 			p.keyMap.Quit,
@@ -237,9 +267,13 @@ func (p *rankingPage) setSize(width, height int) {
 }
 
 func (p *rankingPage) initViewport() {
-	content := renderRankings(p.stage, p.width, p.styles)
 	p.viewport = viewport.New(p.width, p.contentHeight())
-	p.viewport.SetContent(content)
+	p.refreshContent()
+}
+
+func (p *rankingPage) refreshContent() {
+	p.viewport.SetContent(renderRankings(p.stage, p.spoilers, p.width, p.styles))
+	p.spoilersVersion = p.spoilers.version
 }
 
 func (p *rankingPage) contentHeight() int {
@@ -254,10 +288,17 @@ func (p *rankingPage) helpHeight() int {
 	return rankingPageShortHelpHeight + padding
 }
 
-func renderRankings(stage lolesports.Stage, width int, styles rankingPageStyles) string {
+func renderRankings(
+	stage lolesports.Stage,
+	spoilers *spoilerGuard,
+	width int,
+	styles rankingPageStyles,
+) string {
+	isHidden := spoilers.hidesStage(stage)
+
 	rankingTable := make([]*table.Table, len(stage.Sections))
 	for i, section := range stage.Sections {
-		rankingTable[i] = newRankingTable(section.Rankings, width, styles)
+		rankingTable[i] = newRankingTable(section.Rankings, isHidden, width, styles)
 	}
 
 	var sb strings.Builder
@@ -280,8 +321,13 @@ func renderRankings(stage lolesports.Stage, width int, styles rankingPageStyles)
 	return sb.String()
 }
 
+// newRankingTable returns the table of the rankings.
+//
+// When isHidden is true, the teams are sorted by name without their ranking and record
+// so that the results are not spoiled.
 func newRankingTable(
 	rankings []lolesports.Ranking,
+	isHidden bool,
 	width int,
 	styles rankingPageStyles,
 ) *table.Table {
@@ -290,9 +336,19 @@ func newRankingTable(
 	var rows [][]string
 	for _, ranking := range rankings {
 		for _, team := range ranking.Teams {
+			if isHidden {
+				rows = append(rows, []string{
+					hiddenRankingValue,
+					team.Code,
+					hiddenRankingValue,
+					hiddenRankingValue,
+				})
+				continue
+			}
+
 			seriesWinAndLoss := fmt.Sprintf("%dW - %dL", team.Record.Wins, team.Record.Losses)
 
-			winrate := fmt.Sprintf("%d%%", calculateWinrate(team.Record.Wins, team.Record.Losses))
+			winrate := fmt.Sprintf("%d%%", rift.CalculateWinrate(team.Record.Wins, team.Record.Losses))
 
 			row := []string{
 				strconv.Itoa(ranking.Ordinal),
@@ -303,6 +359,9 @@ func newRankingTable(
 			rows = append(rows, row)
 		}
 	}
+	if isHidden {
+		slices.SortFunc(rows, func(a, b []string) int { return strings.Compare(a[1], b[1]) })
+	}
 
 	return table.New().
 		Border(lipgloss.NormalBorder()).
@@ -320,39 +379,8 @@ func newRankingTable(
 		Width(width)
 }
 
-func calculateWinrate(wins, losses int) int {
-	totalGames := wins + losses
-	if totalGames == 0 {
-		return 0
-	}
-	return int(float64(wins) / float64(totalGames) * 100)
-}
-
 func formatTournamentPeriod(startDate, endDate time.Time) string {
 	startMonth := startDate.Format("January")
 	endMonth := endDate.Format("January")
 	return fmt.Sprintf("%s-%s", startMonth, endMonth)
 }
-
-type tournmaentState string
-
-const (
-	tournmaentStateUnknown    = "UNKNOWN"
-	tournmaentStateNotStarted = "UPCOMING"
-	tournmaentStateInProgress = "IN PROGRESS"
-	tournmaentStateCompleted  = "COMPLETED"
-)
-
-func computeTournamentState(startDate, endDate time.Time) tournmaentState {
-	now := time.Now()
-	switch {
-	case now.Before(startDate):
-		return tournmaentStateNotStarted
-	case timeutil.IsCurrentTimeBetween(startDate, endDate):
-		return tournmaentStateInProgress
-	case now.After(endDate):
-		return tournmaentStateCompleted
-	default:
-		return tournmaentStateUnknown
-	}
-}
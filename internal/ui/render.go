@@ -0,0 +1,22 @@
+package ui
+
+import (
+	"github.com/matthieugusmini/go-lolesports"
+
+	"github.com/matthieugusmini/rift/internal/rift"
+)
+
+// RenderRankings renders the ranking tables of every section of the stage
+// using the default styles so they can be displayed outside of the TUI.
+func RenderRankings(stage lolesports.Stage, width int) string {
+	return renderRankings(stage, nil, width, newDefaultRankingPageStyles())
+}
+
+// RenderBracket renders the bracket of a stage from its template and matches
+// using the default styles so it can be displayed outside of the TUI.
+//
+// The bracket is centered when width is larger than the bracket itself.
+func RenderBracket(tmpl rift.BracketTemplate, matches []lolesports.Match, width int) string {
+	// A zero height lets every round take only the height it needs.
+	return renderBracket(tmpl, matches, nil, nil, width, 0, newDefaultBracketPageStyles())
+}
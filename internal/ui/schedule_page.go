@@ -3,6 +3,7 @@ package ui
 import (
 	"context"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/charmbracelet/bubbles/help"
@@ -13,6 +14,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/matthieugusmini/go-lolesports"
 
+	"github.com/matthieugusmini/rift/internal/rift"
 	"github.com/matthieugusmini/rift/internal/timeutil"
 )
 
@@ -25,6 +27,7 @@ const (
 	errMessageFetchInitialPage = "Oups! Looks like something went wrong...\nPress any key to try your luck again"
 	errMessageFetchNextPage    = "Failed to fetch next events. Retry in a moment"
 	errMessageFetchPrevPage    = "Failed to fetch previous events. Retry in a moment"
+	errMessageRefreshPage      = "Failed to refresh events. Results may be outdated"
 )
 
 type schedulePageStyles struct {
@@ -59,9 +62,12 @@ func newDefaultSchedulePageStyles() (s schedulePageStyles) {
 type schedulePageKeyMap struct {
 	list.KeyMap
 
-	RevealSpoiler key.Binding
-	NextPage      key.Binding
-	PrevPage      key.Binding
+	RevealSpoiler  key.Binding
+	RevealBlock    key.Binding
+	RevealUntil    key.Binding
+	ToggleSpoilers key.Binding
+	NextPage       key.Binding
+	PrevPage       key.Binding
 }
 
 func newDefaultSchedulePageKeyMap() schedulePageKeyMap {
@@ -69,7 +75,19 @@ func newDefaultSchedulePageKeyMap() schedulePageKeyMap {
 		KeyMap: list.DefaultKeyMap(),
 		RevealSpoiler: key.NewBinding(
 			key.WithKeys("enter", "right"),
-			key.WithHelp("enter", "reveal spoiler"),
+			key.WithHelp("enter/→", "reveal spoiler"),
+		),
+		RevealBlock: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "reveal block"),
+		),
+		RevealUntil: key.NewBinding(
+			key.WithKeys("U"),
+			key.WithHelp("U", "reveal up to date"),
+		),
+		ToggleSpoilers: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "toggle spoilers"),
 		),
 		PrevPage: key.NewBinding(
 			key.WithKeys("shift+tab"),
@@ -98,6 +116,9 @@ type schedulePage struct {
 	lolesportsClient LoLEsportsLoader
 	logger           *slog.Logger
 
+	// Decides which scores are hidden, shared with the standings.
+	spoilers *spoilerGuard
+
 	width, height int
 
 	// List of all matches fetched so far, filtered to include only events
@@ -115,6 +136,10 @@ type schedulePage struct {
 	// Indicates whether the schedule events have been fetched.
 	loaded bool
 
+	// Indicates whether the displayed events come from the cache and
+	// the fresh initial page is still being fetched in the background.
+	revalidating bool
+
 	// Error message displayed to the user when failed to
 	// load the initial page data.
 	errMsg string
@@ -125,7 +150,11 @@ type schedulePage struct {
 	styles  schedulePageStyles
 }
 
-func newSchedulePage(lolesportsClient LoLEsportsLoader, logger *slog.Logger) *schedulePage {
+func newSchedulePage(
+	lolesportsClient LoLEsportsLoader,
+	spoilers *spoilerGuard,
+	logger *slog.Logger,
+) *schedulePage {
 	styles := newDefaultSchedulePageStyles()
 
 	sp := spinner.New(
@@ -136,6 +165,7 @@ func newSchedulePage(lolesportsClient LoLEsportsLoader, logger *slog.Logger) *sc
 	return &schedulePage{
 		lolesportsClient: lolesportsClient,
 		logger:           logger,
+		spoilers:         spoilers,
 		spinner:          sp,
 		styles:           styles,
 		keyMap:           newDefaultSchedulePageKeyMap(),
@@ -147,7 +177,11 @@ func (p *schedulePage) Init() tea.Cmd {
 	if p.loaded {
 		return nil
 	}
-	return tea.Batch(p.spinner.Tick, p.fetchEvents(pageDirectionInitial))
+	return tea.Batch(
+		p.spinner.Tick,
+		p.loadCachedEvents(),
+		p.fetchEvents(pageDirectionInitial),
+	)
 }
 
 func (p *schedulePage) Update(msg tea.Msg) (page, tea.Cmd) {
@@ -169,6 +203,12 @@ func (p *schedulePage) Update(msg tea.Msg) (page, tea.Cmd) {
 			key.Matches(msg, p.keyMap.CloseFullHelp):
 			p.toggleHelp()
 
+		// The key is part of the filter while filtering.
+		case key.Matches(msg, p.keyMap.ToggleSpoilers) &&
+			p.loaded &&
+			p.matchList.FilterState() != list.Filtering:
+			p.spoilers.toggle()
+
 		case msg.String() == "down":
 			if p.shouldFetchNextPage() {
 				p.paginationState.loadingNextPage = true
@@ -189,8 +229,13 @@ func (p *schedulePage) Update(msg tea.Msg) (page, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 
+	case loadedCachedEventsMessage:
+		cmd := p.handleCachedEvents(msg)
+		cmds = append(cmds, cmd)
+
 	case fetchedEventsMessage:
-		p.handleFetchedEvents(msg)
+		cmd := p.handleFetchedEvents(msg)
+		cmds = append(cmds, cmd)
 
 	case fetchEventsErrorMessage:
 		cmd := p.handleFetchError(msg)
@@ -263,6 +308,7 @@ func (p *schedulePage) shouldFetchNextPage() bool {
 	return p.onLastItem() &&
 		p.paginationState.hasNextPage() &&
 		!p.paginationState.loadingNextPage &&
+		!p.revalidating &&
 		!p.matchList.IsFiltered()
 }
 
@@ -270,6 +316,7 @@ func (p *schedulePage) shouldFetchPreviousPage() bool {
 	return p.onFirstItem() &&
 		p.paginationState.hasPrevPage() &&
 		!p.paginationState.loadingPrevPage &&
+		!p.revalidating &&
 		!p.matchList.IsFiltered()
 }
 
@@ -281,14 +328,41 @@ func (p *schedulePage) onFirstItem() bool {
 	return p.matchList.Index() == 0
 }
 
-func (p *schedulePage) handleFetchedEvents(msg fetchedEventsMessage) {
-	matches := filterMatchEvents(msg.events)
+func (p *schedulePage) handleCachedEvents(msg loadedCachedEventsMessage) tea.Cmd {
+	matches := rift.FilterMatchEvents(msg.events)
+
+	// Nothing to display or the fresh page has already been fetched.
+	if len(matches) == 0 || p.loaded {
+		return nil
+	}
+
+	p.loaded = true
+	p.revalidating = true
+	p.matches = matches
+	p.matchList = newMatchList(matches, p.spoilers, p.keyMap, p.width, p.contentHeight())
+	p.paginationState.prevPageToken = msg.prevPageToken
+	p.paginationState.nextPageToken = msg.nextPageToken
+
+	// Let the user know that the events are being refreshed.
+	return p.matchList.StartSpinner()
+}
+
+func (p *schedulePage) handleFetchedEvents(msg fetchedEventsMessage) tea.Cmd {
+	matches := rift.FilterMatchEvents(msg.events)
 
 	switch msg.pageDirection {
 	case pageDirectionInitial:
+		if p.revalidating {
+			p.revalidating = false
+			p.matchList.StopSpinner()
+			p.paginationState.prevPageToken = msg.prevPageToken
+			p.paginationState.nextPageToken = msg.nextPageToken
+			return p.replaceMatches(matches)
+		}
+
 		p.loaded = true
 		p.matches = matches
-		p.matchList = newMatchList(matches, p.width, p.contentHeight())
+		p.matchList = newMatchList(matches, p.spoilers, p.keyMap, p.width, p.contentHeight())
 		p.paginationState.prevPageToken = msg.prevPageToken
 		p.paginationState.nextPageToken = msg.nextPageToken
 
@@ -304,11 +378,13 @@ func (p *schedulePage) handleFetchedEvents(msg fetchedEventsMessage) {
 		p.paginationState.nextPageToken = msg.nextPageToken
 		p.paginationState.loadingNextPage = false
 	}
+
+	return nil
 }
 
 func (p *schedulePage) prependMatches(events []lolesports.Event) {
 	p.matches = append(events, p.matches...)
-	items := newMatchListItems(p.matches)
+	items := newMatchListItems(p.matches, p.spoilers)
 	p.matchList.SetItems(items)
 	// We should keep the cursor on the previously selected index.
 	p.matchList.Select(p.matchList.Index() + len(events))
@@ -316,10 +392,52 @@ func (p *schedulePage) prependMatches(events []lolesports.Event) {
 
 func (p *schedulePage) appendMatches(events []lolesports.Event) {
 	p.matches = append(p.matches, events...)
-	items := newMatchListItems(p.matches)
+	items := newMatchListItems(p.matches, p.spoilers)
 	p.matchList.SetItems(items)
 }
 
+// replaceMatches replaces the cached matches with the freshly fetched ones
+// (e.g. new results and scores) while keeping the cursor on the same match.
+//
+// The cached matches are not kept as the page tokens only match the fresh page,
+// the following pages would contain some of them again.
+func (p *schedulePage) replaceMatches(events []lolesports.Event) tea.Cmd {
+	// Keep displaying the cached matches rather than an empty list.
+	if len(events) == 0 {
+		return nil
+	}
+
+	var selectedID string
+	if item, ok := p.matchList.SelectedItem().(matchItem); ok {
+		selectedID = item.id
+	}
+
+	p.matches = events
+
+	items := newMatchListItems(p.matches, p.spoilers)
+	cmd := p.matchList.SetItems(items)
+
+	switch p.matchList.FilterState() {
+	case list.Filtering:
+		// The matches are filtered again in the background while the filter is edited.
+		return cmd
+	case list.FilterApplied:
+		// Filter the matches right away to look for the selected one among them.
+		p.matchList.SetFilterText(p.matchList.FilterValue())
+	}
+
+	selectedIndex := slices.IndexFunc(p.matchList.VisibleItems(), func(item list.Item) bool {
+		return item.(matchItem).id == selectedID
+	})
+	switch {
+	case selectedIndex != -1:
+		p.matchList.Select(selectedIndex)
+	case !p.matchList.IsFiltered():
+		p.matchList.Select(indexMatchListInitialCursor(p.matches))
+	}
+	return nil
+}
+
 func (p *schedulePage) handleFetchError(msg fetchEventsErrorMessage) tea.Cmd {
 	var cmd tea.Cmd
 
@@ -332,6 +450,10 @@ func (p *schedulePage) handleFetchError(msg fetchEventsErrorMessage) tea.Cmd {
 
 		var statusMessage string
 		switch msg.pageDirection {
+		case pageDirectionInitial:
+			// Keep displaying the cached events.
+			statusMessage = errMessageRefreshPage
+			p.revalidating = false
 		case pageDirectionNext:
 			statusMessage = errMessageFetchNextPage
 			p.paginationState.loadingNextPage = false
@@ -381,7 +503,13 @@ func (p *schedulePage) FullHelp() [][]key.Binding {
 			p.keyMap.CursorDown,
 			p.keyMap.GoToStart,
 			p.keyMap.GoToEnd,
+		},
+		// Spoilers
+		{
 			p.keyMap.RevealSpoiler,
+			p.keyMap.RevealBlock,
+			p.keyMap.RevealUntil,
+			p.keyMap.ToggleSpoilers,
 		},
 		// App Navigation
 		{
@@ -454,10 +582,31 @@ type (
 
 		pageDirection pageDirection
 	}
+
+	loadedCachedEventsMessage struct {
+		events        []lolesports.Event
+		nextPageToken string
+		prevPageToken string
+	}
 )
 
 // Cmds
 
+func (p *schedulePage) loadCachedEvents() tea.Cmd {
+	return func() tea.Msg {
+		schedule, ok := p.lolesportsClient.GetCachedSchedule(&lolesports.GetScheduleOptions{})
+		if !ok {
+			return nil
+		}
+
+		return loadedCachedEventsMessage{
+			events:        schedule.Events,
+			prevPageToken: schedule.Pages.Older,
+			nextPageToken: schedule.Pages.Newer,
+		}
+	}
+}
+
 func (p *schedulePage) fetchPreviousPageEvents() tea.Cmd {
 	return p.fetchEvents(pageDirectionPrev)
 }
@@ -519,22 +668,3 @@ func formatDateTitle(date time.Time) string {
 		return date.Format("Monday 02 Jan")
 	}
 }
-
-func filterMatchEvents(events []lolesports.Event) []lolesports.Event {
-	matches := make([]lolesports.Event, 0, len(events))
-
-	for _, event := range events {
-		if event.Type != lolesports.EventTypeMatch {
-			continue
-		}
-
-		// Matches does not always have teams somehow.
-		if len(event.Match.Teams) != 2 {
-			continue
-		}
-
-		matches = append(matches, event)
-	}
-
-	return matches
-}
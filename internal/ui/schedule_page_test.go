@@ -0,0 +1,60 @@
+package ui_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matthieugusmini/go-lolesports"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matthieugusmini/rift/internal/ui"
+)
+
+func TestSchedulePage_RevalidateThenPaginate(t *testing.T) {
+	day := time.Now().Add(time.Hour)
+	newEvent := func(id string, daysLater int) lolesports.Event {
+		return lolesports.Event{
+			Type:      lolesports.EventTypeMatch,
+			StartTime: day.AddDate(0, 0, daysLater),
+			Match: lolesports.Match{
+				ID:    id,
+				Teams: []lolesports.Team{{Code: "T1"}, {Code: "GEN"}},
+			},
+		}
+	}
+
+	// The cached page is a few days old, the matches of its end
+	// are now part of the next page.
+	cached := []lolesports.Event{newEvent("1", 0), newEvent("2", 1), newEvent("3", 2)}
+	fresh := []lolesports.Event{newEvent("1", 0), newEvent("2", 1)}
+	next := []lolesports.Event{newEvent("3", 2), newEvent("4", 3)}
+
+	got := ui.SchedulePageMatchIDs(cached, fresh, next)
+
+	assert.Equal(t, []string{"1", "2", "3", "4"}, got)
+}
+
+func TestSchedulePage_RevalidateWhileFiltered(t *testing.T) {
+	day := time.Now().Add(time.Hour)
+	newEvent := func(id, team string, daysLater int) lolesports.Event {
+		return lolesports.Event{
+			Type:      lolesports.EventTypeMatch,
+			StartTime: day.AddDate(0, 0, daysLater),
+			Match: lolesports.Match{
+				ID:    id,
+				Teams: []lolesports.Team{{Code: team}, {Code: "GEN"}},
+			},
+		}
+	}
+
+	events := []lolesports.Event{
+		newEvent("1", "T1", 0),
+		newEvent("2", "FNC", 1),
+		newEvent("3", "T1", 2),
+		newEvent("4", "FNC", 3),
+	}
+
+	got := ui.SchedulePageSelectedMatchID(events, events, "FNC", "4")
+
+	assert.Equal(t, "4", got)
+}
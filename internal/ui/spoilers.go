@@ -0,0 +1,261 @@
+package ui
+
+import (
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/matthieugusmini/go-lolesports"
+)
+
+// Displayed instead of the teams which qualified through a hidden match.
+const hiddenTeamCode = "TBD"
+
+// Key under which the time up to which the results are revealed is persisted.
+const revealedUntilKey = "all"
+
+// spoilerGuard decides which results are hidden across the pages of the application
+// when the spoiler protection is enabled.
+//
+// It is shared by the pages so that revealing a match or toggling the protection
+// applies everywhere. The matches revealed and the time up to which the results
+// are revealed are persisted in the stores if any.
+type spoilerGuard struct {
+	enabled    bool
+	store      RevealedMatchStore
+	untilStore RevealedUntilStore
+	logger     *slog.Logger
+	// IDs of the matches revealed one by one.
+	revealedMatchIDs map[string]bool
+	// IDs of the matches already looked up in the store.
+	restoredMatchIDs map[string]bool
+	// The results of the matches started at or before this time are revealed.
+	revealedUntil time.Time
+	// Start time of the matches seen in the schedule. The matches of the stages have none,
+	// theirs are looked up in the schedule of their league.
+	startTimes map[string]time.Time
+	// Incremented on every change so that the pages know when to render their content again.
+	version int
+}
+
+func newSpoilerGuard(enabled bool, logger *slog.Logger) *spoilerGuard {
+	return &spoilerGuard{
+		enabled:          enabled,
+		logger:           logger,
+		revealedMatchIDs: map[string]bool{},
+		restoredMatchIDs: map[string]bool{},
+		startTimes:       map[string]time.Time{},
+	}
+}
+
+// isHidden returns true if the result of the match must not be displayed.
+//
+// A nil guard hides nothing.
+func (g *spoilerGuard) isHidden(matchID string) bool {
+	if g == nil || !g.enabled || g.revealedMatchIDs[matchID] {
+		return false
+	}
+
+	startTime, ok := g.startTimes[matchID]
+	return !ok || startTime.After(g.revealedUntil)
+}
+
+func (g *spoilerGuard) toggle() {
+	g.enabled = !g.enabled
+	g.version++
+}
+
+func (g *spoilerGuard) reveal(matchIDs ...string) {
+	for _, id := range matchIDs {
+		if g.revealedMatchIDs[id] {
+			continue
+		}
+		g.revealedMatchIDs[id] = true
+		g.persist(id)
+	}
+	g.version++
+}
+
+// revealUntil reveals the results of all the matches started at or before t.
+//
+// The matches seen in the schedule so far are persisted one by one, the others
+// are revealed through the time persisted in the store if any.
+func (g *spoilerGuard) revealUntil(t time.Time) {
+	if !t.After(g.revealedUntil) {
+		return
+	}
+	g.revealedUntil = t
+	g.persistRevealedUntil()
+
+	var ids []string
+	for id, startTime := range g.startTimes {
+		if !startTime.After(t) {
+			ids = append(ids, id)
+		}
+	}
+	g.reveal(ids...)
+}
+
+// observe records the start time of the matches of the events
+// to apply the reveals up to a date to them, and restores the matches
+// revealed in a previous session.
+func (g *spoilerGuard) observe(events []lolesports.Event) {
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.Match.ID
+		if _, ok := g.startTimes[event.Match.ID]; !ok {
+			g.startTimes[event.Match.ID] = event.StartTime
+			g.version++
+		}
+	}
+	g.restore(ids...)
+}
+
+// unobservedMatchIDs returns the set of ids of the played matches of the stages
+// whose start time is unknown.
+func (g *spoilerGuard) unobservedMatchIDs(stages []lolesports.Stage) map[string]bool {
+	ids := make(map[string]bool)
+	for _, stage := range stages {
+		for _, section := range stage.Sections {
+			for _, match := range section.Matches {
+				if _, ok := g.startTimes[match.ID]; !ok && isMatchPlayed(match) {
+					ids[match.ID] = true
+				}
+			}
+		}
+	}
+	return ids
+}
+
+// restore marks the matches found in the store as revealed.
+func (g *spoilerGuard) restore(matchIDs ...string) {
+	if g.store == nil {
+		return
+	}
+
+	for _, id := range matchIDs {
+		if g.restoredMatchIDs[id] || g.revealedMatchIDs[id] {
+			continue
+		}
+		g.restoredMatchIDs[id] = true
+
+		revealed, ok, err := g.store.Get(id)
+		if err != nil {
+			g.logger.Debug(
+				"Revealed match not present in store",
+				slog.Any("err", err),
+				slog.String("matchId", id),
+			)
+		}
+		if ok && revealed {
+			g.revealedMatchIDs[id] = true
+			g.version++
+		}
+	}
+}
+
+func (g *spoilerGuard) persist(matchID string) {
+	if g.store == nil {
+		return
+	}
+
+	if err := g.store.Set(matchID, true); err != nil {
+		g.logger.Warn(
+			"Failed to store revealed match",
+			slog.Any("err", err),
+			slog.String("matchId", matchID),
+		)
+	}
+}
+
+// setRevealedUntilStore persists the time up to which the results are revealed
+// in the store and restores the one of a previous session.
+func (g *spoilerGuard) setRevealedUntilStore(store RevealedUntilStore) {
+	g.untilStore = store
+	if store == nil {
+		return
+	}
+
+	revealedUntil, ok, err := store.Get(revealedUntilKey)
+	if err != nil {
+		g.logger.Debug("Revealed until time not present in store", slog.Any("err", err))
+	}
+	if ok && revealedUntil.After(g.revealedUntil) {
+		g.revealedUntil = revealedUntil
+		g.version++
+	}
+}
+
+func (g *spoilerGuard) persistRevealedUntil() {
+	if g.untilStore == nil {
+		return
+	}
+
+	if err := g.untilStore.Set(revealedUntilKey, g.revealedUntil); err != nil {
+		g.logger.Warn("Failed to store revealed until time", slog.Any("err", err))
+	}
+}
+
+// redactMatch returns a copy of the match of a stage without the result if it is hidden
+// and without the teams if they qualified through a hidden match of the stage.
+func (g *spoilerGuard) redactMatch(
+	match lolesports.Match,
+	stageMatches []lolesports.Match,
+) lolesports.Match {
+	isHidden := g.isHidden(match.ID)
+	hasHiddenTeams := slices.ContainsFunc(stageMatches, func(prev lolesports.Match) bool {
+		return slices.Contains(match.PreviousMatchIDs, prev.ID) &&
+			isMatchPlayed(prev) &&
+			g.isHidden(prev.ID)
+	})
+	if !isHidden && !hasHiddenTeams {
+		return match
+	}
+
+	match.Teams = slices.Clone(match.Teams)
+	for i := range match.Teams {
+		match.Teams[i].Result = nil
+		match.Teams[i].Record = nil
+		if hasHiddenTeams {
+			match.Teams[i].Code = hiddenTeamCode
+			match.Teams[i].Name = ""
+		}
+	}
+	return match
+}
+
+// hidesStage returns true if the rankings of the stage must not be displayed
+// because one of its played matches is hidden.
+func (g *spoilerGuard) hidesStage(stage lolesports.Stage) bool {
+	return slices.ContainsFunc(stage.Sections, func(section lolesports.Section) bool {
+		return g.hidesAnyResult(section.Matches)
+	})
+}
+
+// hidesAnyResult returns true if the result of one of the played matches is hidden.
+func (g *spoilerGuard) hidesAnyResult(matches []lolesports.Match) bool {
+	return slices.ContainsFunc(matches, func(match lolesports.Match) bool {
+		return isMatchPlayed(match) && g.isHidden(match.ID)
+	})
+}
+
+// revealStage reveals the results of all the matches of the stage.
+func (g *spoilerGuard) revealStage(stage lolesports.Stage) {
+	g.reveal(stageMatchIDs(stage)...)
+}
+
+func stageMatchIDs(stage lolesports.Stage) []string {
+	var ids []string
+	for _, section := range stage.Sections {
+		ids = append(ids, matchIDs(section.Matches)...)
+	}
+	return ids
+}
+
+func matchIDs(matches []lolesports.Match) []string {
+	ids := make([]string, len(matches))
+	for i, match := range matches {
+		ids[i] = match.ID
+	}
+	return ids
+}
@@ -0,0 +1,310 @@
+package ui_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matthieugusmini/go-lolesports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matthieugusmini/rift/internal/ui"
+)
+
+func TestSpoilerGuard_IsHidden(t *testing.T) {
+	day := time.Date(2025, time.January, 18, 17, 0, 0, 0, time.UTC)
+	events := []lolesports.Event{
+		{StartTime: day, Match: lolesports.Match{ID: "1"}},
+		{StartTime: day.Add(24 * time.Hour), Match: lolesports.Match{ID: "2"}},
+	}
+
+	t.Run("hides every match by default", func(t *testing.T) {
+		guard := ui.NewSpoilerGuard(true, nil)
+		guard.Observe(events)
+
+		assert.True(t, guard.IsHidden("1"))
+		assert.True(t, guard.IsHidden("2"))
+		assert.True(t, guard.IsHidden("3"))
+	})
+
+	t.Run("hides nothing when disabled", func(t *testing.T) {
+		guard := ui.NewSpoilerGuard(false, nil)
+
+		assert.False(t, guard.IsHidden("1"))
+	})
+
+	t.Run("hides nothing when toggled off", func(t *testing.T) {
+		guard := ui.NewSpoilerGuard(true, nil)
+		guard.Toggle()
+
+		assert.False(t, guard.IsHidden("1"))
+	})
+
+	t.Run("reveals matches one by one", func(t *testing.T) {
+		guard := ui.NewSpoilerGuard(true, nil)
+		guard.Reveal("2")
+
+		assert.True(t, guard.IsHidden("1"))
+		assert.False(t, guard.IsHidden("2"))
+	})
+
+	t.Run("reveals matches up to date", func(t *testing.T) {
+		guard := ui.NewSpoilerGuard(true, nil)
+		guard.Observe(events)
+		guard.RevealUntil(day)
+
+		assert.False(t, guard.IsHidden("1"))
+		assert.True(t, guard.IsHidden("2"))
+		// The start time of the matches not seen in the schedule is unknown.
+		assert.True(t, guard.IsHidden("3"))
+	})
+}
+
+func TestSpoilerGuard_RedactMatch(t *testing.T) {
+	semifinal1 := newPlayedTestMatch("1")
+	semifinal2 := lolesports.Match{ID: "2", Teams: []lolesports.Team{{Code: "G2"}, {Code: "FNC"}}}
+	final := lolesports.Match{
+		ID:               "3",
+		PreviousMatchIDs: []string{"1", "2"},
+		Teams:            []lolesports.Team{{Code: "T1", Name: "T1"}, {Code: "TBD"}},
+	}
+	stage := []lolesports.Match{semifinal1, semifinal2, final}
+
+	t.Run("hides result of played match", func(t *testing.T) {
+		guard := ui.NewSpoilerGuard(true, nil)
+
+		got := guard.RedactMatch(semifinal1, stage)
+
+		for _, team := range got.Teams {
+			assert.Nil(t, team.Result)
+		}
+		assert.Equal(t, "T1", got.Teams[0].Code)
+		assert.NotNil(t, semifinal1.Teams[0].Result, "the match of the stage must not be modified")
+	})
+
+	t.Run("hides teams qualified through hidden match", func(t *testing.T) {
+		guard := ui.NewSpoilerGuard(true, nil)
+
+		got := guard.RedactMatch(final, stage)
+
+		assert.Equal(t, "TBD", got.Teams[0].Code)
+		assert.Empty(t, got.Teams[0].Name)
+	})
+
+	t.Run("shows teams once previous matches revealed", func(t *testing.T) {
+		guard := ui.NewSpoilerGuard(true, nil)
+		guard.Reveal("1")
+
+		got := guard.RedactMatch(final, stage)
+
+		assert.Equal(t, "T1", got.Teams[0].Code)
+	})
+
+	t.Run("keeps match unchanged when disabled", func(t *testing.T) {
+		guard := ui.NewSpoilerGuard(false, nil)
+
+		assert.Equal(t, semifinal1, guard.RedactMatch(semifinal1, stage))
+		assert.Equal(t, final, guard.RedactMatch(final, stage))
+	})
+}
+
+func TestSpoilerGuard_HidesStage(t *testing.T) {
+	stage := lolesports.Stage{
+		Sections: []lolesports.Section{
+			{Matches: []lolesports.Match{newPlayedTestMatch("1"), {ID: "2"}}},
+		},
+	}
+
+	guard := ui.NewSpoilerGuard(true, nil)
+	assert.True(t, guard.HidesStage(stage))
+
+	// The unplayed matches have no result to hide.
+	guard.Reveal("1")
+	assert.False(t, guard.HidesStage(stage))
+}
+
+func TestSpoilerGuard_ObserveStages(t *testing.T) {
+	day := time.Date(2025, time.January, 18, 17, 0, 0, 0, time.UTC)
+	stage := lolesports.Stage{
+		Sections: []lolesports.Section{
+			{Matches: []lolesports.Match{newPlayedTestMatch("1"), newPlayedTestMatch("2")}},
+		},
+	}
+	loader := stubScheduleLoader{
+		"": {
+			Pages:  lolesports.Pages{Older: "older"},
+			Events: []lolesports.Event{{StartTime: day, Match: lolesports.Match{ID: "2"}}},
+		},
+		"older": {
+			Events: []lolesports.Event{
+				{StartTime: day.Add(-24 * time.Hour), Match: lolesports.Match{ID: "1"}},
+			},
+		},
+	}
+
+	guard := ui.NewSpoilerGuard(true, nil)
+	guard.ObserveStages(loader, "lec", []lolesports.Stage{stage})
+	guard.RevealUntil(day.Add(-time.Hour))
+
+	assert.False(t, guard.IsHidden("1"))
+	assert.True(t, guard.IsHidden("2"))
+}
+
+func TestSpoilerGuard_RevealedMatchStore(t *testing.T) {
+	day := time.Date(2025, time.January, 18, 17, 0, 0, 0, time.UTC)
+	events := []lolesports.Event{
+		{StartTime: day, Match: lolesports.Match{ID: "1"}},
+		{StartTime: day.Add(24 * time.Hour), Match: lolesports.Match{ID: "2"}},
+		{StartTime: day.Add(48 * time.Hour), Match: lolesports.Match{ID: "3"}},
+	}
+
+	t.Run("persists revealed matches", func(t *testing.T) {
+		store := fakeRevealedMatchStore{}
+		guard := ui.NewSpoilerGuard(true, store)
+		guard.Observe(events)
+
+		guard.Reveal("3")
+		guard.RevealUntil(day)
+
+		assert.Equal(t, fakeRevealedMatchStore{"1": true, "3": true}, store)
+	})
+
+	t.Run("restores revealed matches", func(t *testing.T) {
+		store := fakeRevealedMatchStore{"1": true, "3": true}
+		guard := ui.NewSpoilerGuard(true, store)
+
+		guard.Observe(events)
+
+		assert.False(t, guard.IsHidden("1"))
+		assert.True(t, guard.IsHidden("2"))
+		assert.False(t, guard.IsHidden("3"))
+	})
+
+	t.Run("keeps revealing when store fails", func(t *testing.T) {
+		guard := ui.NewSpoilerGuard(true, failingRevealedMatchStore{})
+		guard.Observe(events)
+
+		guard.Reveal("1")
+
+		require.False(t, guard.IsHidden("1"))
+		assert.True(t, guard.IsHidden("2"))
+	})
+}
+
+func TestSpoilerGuard_RevealedUntilStore(t *testing.T) {
+	day := time.Date(2025, time.January, 18, 17, 0, 0, 0, time.UTC)
+	events := []lolesports.Event{
+		{StartTime: day, Match: lolesports.Match{ID: "1"}},
+		{StartTime: day.Add(24 * time.Hour), Match: lolesports.Match{ID: "2"}},
+	}
+
+	t.Run("persists revealed until time", func(t *testing.T) {
+		store := fakeRevealedUntilStore{}
+		guard := ui.NewSpoilerGuard(true, nil)
+		guard.SetRevealedUntilStore(store)
+
+		guard.RevealUntil(day)
+
+		assert.Equal(t, fakeRevealedUntilStore{"all": day}, store)
+	})
+
+	t.Run("restores revealed until time", func(t *testing.T) {
+		guard := ui.NewSpoilerGuard(true, nil)
+		guard.SetRevealedUntilStore(fakeRevealedUntilStore{"all": day})
+
+		guard.Observe(events)
+
+		assert.False(t, guard.IsHidden("1"))
+		assert.True(t, guard.IsHidden("2"))
+	})
+
+	t.Run("keeps revealing when store fails", func(t *testing.T) {
+		guard := ui.NewSpoilerGuard(true, nil)
+		guard.SetRevealedUntilStore(failingRevealedUntilStore{})
+		guard.Observe(events)
+
+		guard.RevealUntil(day)
+
+		require.False(t, guard.IsHidden("1"))
+		assert.True(t, guard.IsHidden("2"))
+	})
+}
+
+type fakeRevealedMatchStore map[string]bool
+
+func (s fakeRevealedMatchStore) Get(matchID string) (bool, bool, error) {
+	revealed, ok := s[matchID]
+	return revealed, ok, nil
+}
+
+func (s fakeRevealedMatchStore) Set(matchID string, revealed bool) error {
+	s[matchID] = revealed
+	return nil
+}
+
+type failingRevealedMatchStore struct{}
+
+func (failingRevealedMatchStore) Get(string) (bool, bool, error) {
+	return false, false, errors.New("store unavailable")
+}
+
+func (failingRevealedMatchStore) Set(string, bool) error {
+	return errors.New("store unavailable")
+}
+
+type fakeRevealedUntilStore map[string]time.Time
+
+func (s fakeRevealedUntilStore) Get(key string) (time.Time, bool, error) {
+	t, ok := s[key]
+	return t, ok, nil
+}
+
+func (s fakeRevealedUntilStore) Set(key string, t time.Time) error {
+	s[key] = t
+	return nil
+}
+
+type failingRevealedUntilStore struct{}
+
+func (failingRevealedUntilStore) Get(string) (time.Time, bool, error) {
+	return time.Time{}, false, errors.New("store unavailable")
+}
+
+func (failingRevealedUntilStore) Set(string, time.Time) error {
+	return errors.New("store unavailable")
+}
+
+// stubScheduleLoader serves the pages of a schedule indexed by page token.
+type stubScheduleLoader map[string]lolesports.Schedule
+
+func (l stubScheduleLoader) GetSchedule(
+	_ context.Context,
+	opts *lolesports.GetScheduleOptions,
+) (lolesports.Schedule, error) {
+	var token string
+	if opts.PageToken != nil {
+		token = *opts.PageToken
+	}
+	return l[token], nil
+}
+
+func (l stubScheduleLoader) GetCachedSchedule(
+	*lolesports.GetScheduleOptions,
+) (lolesports.Schedule, bool) {
+	return lolesports.Schedule{}, false
+}
+
+func (l stubScheduleLoader) LoadStandingsByTournamentIDs(
+	context.Context,
+	[]string,
+) ([]lolesports.Standings, error) {
+	return nil, nil
+}
+
+func (l stubScheduleLoader) LoadCurrentSeasonSplits(context.Context) ([]lolesports.Split, error) {
+	return nil, nil
+}
+
+func (l stubScheduleLoader) StaleSince() (time.Time, bool) { return time.Time{}, false }
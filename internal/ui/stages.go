@@ -3,7 +3,6 @@ package ui
 import (
 	"fmt"
 	"io"
-	"slices"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
@@ -11,6 +10,8 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/matthieugusmini/go-lolesports"
+
+	"github.com/matthieugusmini/rift/internal/rift"
 )
 
 type stageType string
@@ -24,19 +25,28 @@ type stageItem struct {
 	name      string
 	stageType stageType
 	disabled  bool
+	// Whether the bracket is displayed with a generated template.
+	generated bool
 }
 
 func (i stageItem) Title() string { return i.name }
 
-func (i stageItem) Description() string { return string(i.stageType) }
+func (i stageItem) Description() string {
+	if i.generated {
+		return string(i.stageType) + " (AUTO)"
+	}
+	return string(i.stageType)
+}
 
 func (i stageItem) FilterValue() string { return i.name }
 
 func (i stageItem) isDisabled() bool { return i.disabled }
 
+// newStageOptionsList returns the list of the stages given the set of ids of the
+// stages with a curated bracket template, nil if it is not known yet.
 func newStageOptionsList(
 	stages []lolesports.Stage,
-	availableStages []string,
+	availableStages map[string]bool,
 	width, height int,
 ) list.Model {
 	stageItems := make([]list.Item, len(stages))
@@ -44,7 +54,8 @@ func newStageOptionsList(
 		item := stageItem{
 			name:      stage.Name,
 			stageType: getStageType(stage),
-			disabled:  !isAvailableBracketStage(stage, availableStages),
+			disabled:  !isAvailableBracketStage(stage),
+			generated: hasGeneratedBracketTemplate(stage, availableStages),
 		}
 		stageItems[i] = item
 	}
@@ -175,15 +186,29 @@ func (d stageItemDelegate) Render(w io.Writer, m list.Model, index int, item lis
 }
 
 func getStageType(stage lolesports.Stage) stageType {
-	if len(stage.Sections) > 0 && len(stage.Sections[0].Rankings) == 0 {
+	if rift.IsBracketStage(stage) {
 		return stageTypeBracket
 	}
 	return stageTypeGroups
 }
 
-func isAvailableBracketStage(stage lolesports.Stage, availableStages []string) bool {
+// isAvailableBracketStage returns false for the bracket stages which cannot be displayed
+// because their matches are not known yet, true otherwise.
+func isAvailableBracketStage(stage lolesports.Stage) bool {
 	if getStageType(stage) == stageTypeBracket {
-		return slices.Contains(availableStages, stage.ID)
+		// Bracket stages always have a single section.
+		return len(stage.Sections) > 0 && len(stage.Sections[0].Matches) > 0
 	}
 	return true
 }
+
+// hasGeneratedBracketTemplate returns true for the bracket stages without
+// a curated template which are displayed with a generated one instead.
+//
+// It returns false if the stages with a curated template are not known.
+func hasGeneratedBracketTemplate(stage lolesports.Stage, availableStages map[string]bool) bool {
+	if availableStages == nil {
+		return false
+	}
+	return getStageType(stage) == stageTypeBracket && !availableStages[stage.ID]
+}
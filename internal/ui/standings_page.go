@@ -2,6 +2,7 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	"github.com/charmbracelet/bubbles/help"
@@ -26,6 +27,9 @@ const (
 
 const (
 	errMessageFetchError = "Oups! Something went wrong...\nPress any key to try your luck again."
+
+	errMessageInvalidBracketTemplate = "Oups! This bracket cannot be displayed yet...\n" +
+		"Press any key to go back."
 )
 
 const (
@@ -121,11 +125,15 @@ type standingsPage struct {
 	leagueOptions list.Model
 	stageOptions  list.Model
 
-	availableBracketStageIDs []string
+	// Set of ids of the stages with a curated bracket template,
+	// nil until they have been fetched.
+	availableBracketStageIDs map[string]bool
 
 	rankingView *rankingPage
 	bracket     *bracketPage
 
+	spoilers *spoilerGuard
+
 	errMsg string
 
 	spinner spinner.Model
@@ -141,6 +149,7 @@ type standingsPage struct {
 func newStandingsPage(
 	lolesportsClient LoLEsportsLoader,
 	bracketLoader BracketTemplateLoader,
+	spoilers *spoilerGuard,
 	logger *slog.Logger,
 ) *standingsPage {
 	styles := newDefaultStandingsStyles()
@@ -153,6 +162,7 @@ func newStandingsPage(
 	return &standingsPage{
 		lolesportsClient:      lolesportsClient,
 		bracketTemplateLoader: bracketLoader,
+		spoilers:              spoilers,
 		logger:                logger,
 		styles:                styles,
 		spinner:               sp,
@@ -191,6 +201,9 @@ func (p *standingsPage) Update(msg tea.Msg) (page, tea.Cmd) {
 			key.Matches(msg, p.keyMap.CloseFullHelp):
 			p.toggleFullHelp()
 
+		case key.Matches(msg, p.keyMap.ToggleSpoilers):
+			p.spoilers.toggle()
+
 		case key.Matches(msg, p.keyMap.Previous):
 			if !p.isShowingSubModel() || (p.isShowingSubModel() && p.isSubModelPreviousKey(msg)) {
 				p.goToPreviousStep()
@@ -211,7 +224,10 @@ func (p *standingsPage) Update(msg tea.Msg) (page, tea.Cmd) {
 		p.handleSplitsLoaded(msg)
 
 	case loadedStandingsMessage:
-		p.handleStandingsLoaded(msg)
+		cmds = append(cmds, p.handleStandingsLoaded(msg))
+
+	case fetchedStageMatchEventsMessage:
+		p.spoilers.observe(msg.events)
 
 	case fetchedAvailableStageTemplates:
 		p.handleAvailableStageTemplates(msg)
@@ -255,20 +271,34 @@ func (p *standingsPage) handleSplitsLoaded(msg fetchedCurrentSeasonSplitsMessage
 	p.splitOptions = newSplitOptionsList(p.splits, p.listWidth(), p.listHeight())
 }
 
-func (p *standingsPage) handleStandingsLoaded(msg loadedStandingsMessage) {
+func (p *standingsPage) handleStandingsLoaded(msg loadedStandingsMessage) tea.Cmd {
 	p.state = standingsPageStateStageSelection
 
-	p.stages = listStagesFromStandings(msg.standings)
+	p.stages = rift.ListStagesFromStandings(msg.standings)
 	p.stageOptions = newStageOptionsList(
 		p.stages,
 		p.availableBracketStageIDs,
 		p.listWidth(),
 		p.listHeight(),
 	)
+
+	// The results revealed up to a date apply to the matches of the stages
+	// once their start time is known.
+	if !p.spoilers.enabled {
+		return nil
+	}
+	matchIDs := p.spoilers.unobservedMatchIDs(p.stages)
+	if len(matchIDs) == 0 {
+		return nil
+	}
+	return p.fetchStageMatchEvents(p.selectedLeague().ID, matchIDs)
 }
 
 func (p *standingsPage) handleAvailableStageTemplates(msg fetchedAvailableStageTemplates) {
-	p.availableBracketStageIDs = msg.availableTemplates
+	p.availableBracketStageIDs = make(map[string]bool, len(msg.availableTemplates))
+	for _, stageID := range msg.availableTemplates {
+		p.availableBracketStageIDs[stageID] = true
+	}
 	p.stageOptions = newStageOptionsList(
 		p.stages,
 		p.availableBracketStageIDs,
@@ -280,13 +310,24 @@ func (p *standingsPage) handleAvailableStageTemplates(msg fetchedAvailableStageT
 func (p *standingsPage) handleBracketTemplateLoaded(msg loadedBracketStageTemplateMessage) {
 	p.state = standingsPageStateShowBracketPage
 
+	// The template tells for sure whether it has been generated, even if the stages
+	// with a curated template could not be fetched.
+	if item, ok := p.stageOptions.SelectedItem().(stageItem); ok {
+		item.generated = msg.template.Generated
+		p.stageOptions.SetItem(p.stageOptions.Index(), item)
+	}
+
 	// Bracket stages always have a single section.
 	matches := p.selectedStage().Sections[0].Matches
-	p.bracket = newBracketPage(msg.template, matches, p.width, p.height)
+	p.bracket = newBracketPage(msg.template, matches, p.spoilers, p.width, p.height)
 }
 
 func (p *standingsPage) handleErrorMessage(msg fetchErrorMessage) {
 	p.errMsg = errMessageFetchError
+	if errors.Is(msg.err, rift.ErrInvalidBracketTemplate) {
+		// Trying again would not help, the template has to be fixed.
+		p.errMsg = errMessageInvalidBracketTemplate
+	}
 
 	// Revert to previous state.
 	switch p.state {
@@ -318,14 +359,14 @@ func (p *standingsPage) handleSelection() tea.Cmd {
 func (p *standingsPage) selectSplit() {
 	p.state = standingsPageStateLeagueSelection
 
-	p.leagues = listLeaguesFromTournaments(p.selectedSplit().Tournaments)
+	p.leagues = rift.ListLeaguesFromTournaments(p.selectedSplit().Tournaments)
 	p.leagueOptions = newLeagueOptionsList(p.leagues, p.listWidth(), p.listHeight())
 }
 
 func (p *standingsPage) selectLeague() tea.Cmd {
 	p.state = standingsPageStateLoadingStages
 
-	tournamentIDs := listTournamentIDsForLeague(
+	tournamentIDs := rift.ListTournamentIDsForLeague(
 		p.selectedSplit().Tournaments,
 		p.selectedLeague().ID,
 	)
@@ -345,19 +386,20 @@ func (p *standingsPage) selectStage() tea.Cmd {
 			p.selectedSplit(),
 			p.selectedLeague(),
 			p.selectedStage(),
+			p.spoilers,
 			p.width,
 			p.height,
 		)
 		p.state = standingsPageStateShowRankingPage
 
 	case stageTypeBracket:
-		// Disable click on unsupported stages.
-		if !isAvailableBracketStage(p.selectedStage(), p.availableBracketStageIDs) {
+		// Disable click on the stages whose matches are not known yet.
+		if !isAvailableBracketStage(p.selectedStage()) {
 			return nil
 		}
 
 		p.state = standingsPageStateLoadingBracketTemplate
-		return p.loadBracketStageTemplate(p.selectedStage().ID)
+		return p.loadBracketStageTemplate(p.selectedStage())
 	}
 
 	return nil
@@ -475,7 +517,7 @@ func (p *standingsPage) viewSelectionPrompt() string {
 	case standingsPageStateLeagueSelection:
 		prompt = p.styles.prompt.Render(captionSelectLeague)
 	case standingsPageStateStageSelection:
-		if isAvailableBracketStage(p.selectedStage(), p.availableBracketStageIDs) {
+		if isAvailableBracketStage(p.selectedStage()) {
 			prompt = p.styles.prompt.Render(captionSelectStage)
 		} else {
 			prompt = p.styles.prompt.Render(captionUnavailableStageBracket)
@@ -592,6 +634,7 @@ func (p *standingsPage) FullHelp() [][]key.Binding {
 		},
 		// Others
 		{
+			p.keyMap.ToggleSpoilers,
 			p.keyMap.Quit,
 			p.keyMap.CloseFullHelp,
 		},
@@ -649,6 +692,7 @@ type (
 	fetchedAvailableStageTemplates    struct{ availableTemplates []string }
 	loadedBracketStageTemplateMessage struct{ template rift.BracketTemplate }
 	loadedStandingsMessage            struct{ standings []lolesports.Standings }
+	fetchedStageMatchEventsMessage    struct{ events []lolesports.Event }
 	fetchErrorMessage                 struct{ err error }
 )
 
@@ -667,6 +711,70 @@ func (p *standingsPage) loadStandings(tournamentIDs []string) tea.Cmd {
 	}
 }
 
+// maxStageSchedulePages is the maximum number of pages of the schedule of a league
+// fetched to find the matches of its stages.
+const maxStageSchedulePages = 10
+
+// fetchStageMatchEvents fetches the schedule of the league from the current page
+// backward until the events of all the given matches are found.
+func (p *standingsPage) fetchStageMatchEvents(leagueID string, matchIDs map[string]bool) tea.Cmd {
+	return func() tea.Msg {
+		events := fetchMatchEvents(
+			context.Background(),
+			p.lolesportsClient,
+			leagueID,
+			matchIDs,
+			p.logger,
+		)
+		return fetchedStageMatchEventsMessage{events}
+	}
+}
+
+// fetchMatchEvents returns the events of the given matches found in the schedule
+// of the league, matchIDs is emptied along the way.
+//
+// Errors are only logged since the matches just stay hidden until revealed one by one.
+func fetchMatchEvents(
+	ctx context.Context,
+	loader LoLEsportsLoader,
+	leagueID string,
+	matchIDs map[string]bool,
+	logger *slog.Logger,
+) []lolesports.Event {
+	var (
+		events []lolesports.Event
+		opts   = &lolesports.GetScheduleOptions{LeagueIDs: []string{leagueID}}
+	)
+	for range maxStageSchedulePages {
+		schedule, err := loader.GetSchedule(ctx, opts)
+		if err != nil {
+			logger.Warn(
+				"Failed to fetch the schedule of the matches of the stages",
+				slog.Any("err", err),
+				slog.String("leagueId", leagueID),
+			)
+			break
+		}
+
+		for _, event := range schedule.Events {
+			if matchIDs[event.Match.ID] {
+				events = append(events, event)
+				delete(matchIDs, event.Match.ID)
+			}
+		}
+		if len(matchIDs) == 0 || schedule.Pages.Older == "" {
+			break
+		}
+
+		opts = &lolesports.GetScheduleOptions{
+			LeagueIDs: []string{leagueID},
+			PageToken: &schedule.Pages.Older,
+		}
+	}
+
+	return events
+}
+
 func (p *standingsPage) fetchCurrentSeasonSplits() tea.Cmd {
 	return func() tea.Msg {
 		splits, err := p.lolesportsClient.LoadCurrentSeasonSplits(context.Background())
@@ -683,50 +791,24 @@ func (p *standingsPage) fetchAvailableStageTemplates() tea.Cmd {
 			context.Background(),
 		)
 		if err != nil {
-			return fetchErrorMessage{err: err}
+			// Not being able to fetch the templates only disables the bracket stages,
+			// the other stages can still be displayed (e.g. when offline).
+			p.logger.Warn("Failed to fetch available stage templates", slog.Any("error", err))
+			return nil
 		}
 		return fetchedAvailableStageTemplates{availableTemplates: availableStageIDs}
 	}
 }
 
-func (p *standingsPage) loadBracketStageTemplate(stageID string) tea.Cmd {
+func (p *standingsPage) loadBracketStageTemplate(stage lolesports.Stage) tea.Cmd {
+	// Bracket stages always have a single section.
+	matches := stage.Sections[0].Matches
+
 	return func() tea.Msg {
-		tmpl, err := p.bracketTemplateLoader.Load(context.Background(), stageID)
+		tmpl, err := p.bracketTemplateLoader.Load(context.Background(), stage.ID, matches)
 		if err != nil {
 			return fetchErrorMessage{err: err}
 		}
 		return loadedBracketStageTemplateMessage{tmpl}
 	}
 }
-
-func listLeaguesFromTournaments(tournaments []lolesports.Tournament) []lolesports.League {
-	var (
-		leagues     []lolesports.League
-		seenLeagues = map[string]bool{}
-	)
-	for _, tournament := range tournaments {
-		if _, ok := seenLeagues[tournament.League.ID]; !ok {
-			leagues = append(leagues, tournament.League)
-			seenLeagues[tournament.League.ID] = true
-		}
-	}
-	return leagues
-}
-
-func listTournamentIDsForLeague(tournaments []lolesports.Tournament, leagueID string) []string {
-	var tournamentIDs []string
-	for _, tournament := range tournaments {
-		if tournament.League.ID == leagueID {
-			tournamentIDs = append(tournamentIDs, tournament.ID)
-		}
-	}
-	return tournamentIDs
-}
-
-func listStagesFromStandings(standings []lolesports.Standings) []lolesports.Stage {
-	var stages []lolesports.Stage
-	for _, standing := range standings {
-		stages = append(stages, standing.Stages...)
-	}
-	return stages
-}
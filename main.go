@@ -1,22 +1,34 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/matthieugusmini/go-lolesports"
 	gap "github.com/muesli/go-app-paths"
 	"go.etcd.io/bbolt"
+	bolterrors "go.etcd.io/bbolt/errors"
 
 	"github.com/matthieugusmini/rift/internal/cache"
+	"github.com/matthieugusmini/rift/internal/cli"
 	"github.com/matthieugusmini/rift/internal/githubusercontent"
+	"github.com/matthieugusmini/rift/internal/httpretry"
 	"github.com/matthieugusmini/rift/internal/rift"
+	"github.com/matthieugusmini/rift/internal/templatefs"
 	"github.com/matthieugusmini/rift/internal/ui"
 )
 
@@ -28,21 +40,53 @@ var (
 
 const appName = "rift"
 
+const lolesportsModulePath = "github.com/matthieugusmini/go-lolesports"
+
 const logFilename = "rift.log"
 
 const (
-	cacheFile = "rift.db"
+	cacheBackendBolt   = "bolt"
+	cacheBackendDir    = "dir"
+	cacheBackendMemory = "memory"
 
-	bucketBracketTemplate = "bracketTemplate"
-	bucketStandings       = "standings"
-	bucketSchedule        = "schedule"
-	bucketSplits          = "splits"
+	cacheFile = "rift.db"
+	// Written next to the cache database by the process holding its lock.
+	cacheLockHolderFile = "rift.db.pid"
+	// Directory storing the entries with the dir backend.
+	cacheEntriesDir = "entries"
+
+	bucketBracketTemplate          = "bracketTemplate"
+	bucketBracketTemplateResponses = "bracketTemplateResponses"
+	bucketAvailableStageIDs        = "availableStageIds"
+	bucketStandings                = "standings"
+	bucketSchedule                 = "schedule"
+	bucketSplits                   = "splits"
+	bucketRevealedMatches          = "revealedMatches"
+	bucketRevealedUntil            = "revealedUntil"
 
 	cacheDefaultTTL = 12 * time.Hour
+
+	// Schedule pages are always revalidated against the API when displayed,
+	// the cached version is only used to render something right away.
+	cacheScheduleTTL = 7 * 24 * time.Hour
+
+	// Expired entries are kept for a while to be displayed in offline mode.
+	cacheDefaultRetention = 30 * 24 * time.Hour
+	cacheDefaultMaxSizeMB = 100
+	cacheCleanInterval    = time.Hour
+
+	// How long to wait for another process to release the lock on the cache.
+	cacheOpenTimeout = time.Second
+
+	// Maximum number of decoded entries of each bucket kept in memory.
+	cacheMemoryCapacity = 64
 )
 
 const (
-	httpClientDefaultTimeout = 10 * time.Second
+	// Maximum duration of a single attempt of an HTTP request.
+	httpAttemptTimeout = 10 * time.Second
+	// Maximum time spent on an HTTP request across all its attempts.
+	httpMaxElapsedTime = 30 * time.Second
 )
 
 func main() {
@@ -53,6 +97,52 @@ func main() {
 }
 
 func run() error {
+	offline := flag.Bool(
+		"offline",
+		false,
+		"Never access the network and only display the data available in the cache",
+	)
+	cacheRetention := flag.Duration(
+		"cache-retention",
+		cacheDefaultRetention,
+		"How long expired cache entries are kept to be displayed in offline mode",
+	)
+	cacheMaxSizeMB := flag.Int(
+		"cache-max-size",
+		cacheDefaultMaxSizeMB,
+		"Maximum size of the cached entries in MB, the least recently used entries are removed "+
+			"first (0 = unlimited)",
+	)
+	cacheBackendName := flag.String(
+		"cache-backend",
+		cacheBackendBolt,
+		"Storage of the cache: bolt (single file), dir (one file per entry) "+
+			"or memory (not persisted)",
+	)
+	cacheCompression := flag.Bool(
+		"cache-compression",
+		true,
+		"Compress the cached data, entries stored with or without compression are always readable",
+	)
+	bracketTemplatesDir := flag.String(
+		"bracket-templates-dir",
+		"",
+		"Directory of bracket templates overriding the published ones, "+
+			"laid out like the templates repository",
+	)
+	spoilerProtection := flag.Bool(
+		"spoiler-protection",
+		true,
+		"Hide the results of the matches until revealed, can be toggled with s",
+	)
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() > 0 && !cli.IsCommand(flag.Arg(0)) {
+		flag.Usage()
+		return fmt.Errorf("unknown command %q", flag.Arg(0))
+	}
+
 	scope := gap.NewScope(gap.User, appName)
 
 	logger, logFile, err := initLogger(scope)
@@ -61,30 +151,118 @@ func run() error {
 	}
 	defer logFile.Close()
 
-	cacheDB, err := initCache(scope)
+	// The cache command must manage the actual cache, not an in-memory one.
+	isCacheCommand := flag.NArg() > 0 && flag.Arg(0) == "cache"
+	cacheBackend, cacheCloser, err := initCache(
+		scope,
+		*cacheBackendName,
+		!isCacheCommand,
+		logger,
+	)
 	if err != nil {
 		return fmt.Errorf("could not initialize the cache: %w", err)
 	}
-	defer cacheDB.Close()
+	defer cacheCloser.Close()
 
 	httpClient := &http.Client{
-		Timeout: httpClientDefaultTimeout,
+		Transport: httpretry.NewTransport(
+			http.DefaultTransport,
+			logger,
+			httpretry.WithAttemptTimeout(httpAttemptTimeout),
+			httpretry.WithMaxElapsedTime(httpMaxElapsedTime),
+		),
+	}
+	if *offline {
+		// Failing is not transient in offline mode, there is no point in retrying.
+		httpClient.Transport = offlineTransport{}
+	}
+
+	var cacheOpts []cache.Option
+	if *cacheCompression {
+		cacheOpts = append(cacheOpts, cache.WithCompression())
 	}
+	caches := newCaches(cacheBackend, cacheOpts...)
+	defer caches.flush(logger)
+
+	janitor := cache.NewJanitor(
+		caches.maintainable(),
+		logger,
+		cache.WithRetention(*cacheRetention),
+		cache.WithMaxSize(*cacheMaxSizeMB*1024*1024),
+	)
 
-	bracketTemplateLoader := initBracketTemplateLoader(httpClient, cacheDB, logger)
+	bracketTemplateLoader := initBracketTemplateLoader(
+		httpClient,
+		caches,
+		*bracketTemplatesDir,
+		logger,
+	)
+
+	lolesportsLoader := initLoLEsportsLoader(httpClient, caches, logger)
+
+	if flag.NArg() > 0 {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
 
-	lolesportsLoader := initLoLEsportsLoader(httpClient, cacheDB, logger)
+		// Leave the entries untouched for the cache command to manage them.
+		if !isCacheCommand && !cacheBackend.IsReadOnly() {
+			janitor.Run(ctx, 0)
+		}
 
-	m := ui.NewModel(lolesportsLoader, bracketTemplateLoader, logger)
+		app := cli.New(
+			lolesportsLoader,
+			bracketTemplateLoader,
+			cli.WithCaches(caches.byBucket()),
+			cli.WithPreservedBuckets(bucketRevealedMatches, bucketRevealedUntil),
+		)
+		return app.Run(ctx, flag.Args())
+	}
+
+	m := ui.NewModel(
+		lolesportsLoader,
+		bracketTemplateLoader,
+		logger,
+		ui.WithOffline(*offline),
+		ui.WithSpoilerProtection(*spoilerProtection),
+		ui.WithRevealedMatchStore(caches.revealedMatches),
+		ui.WithRevealedUntilStore(caches.revealedUntil),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if !cacheBackend.IsReadOnly() {
+		go janitor.Run(ctx, cacheCleanInterval)
+	}
 
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		return err
 	}
 
+	caches.logCounters(logger)
+
 	return nil
 }
 
+var errOffline = errors.New("network access is disabled in offline mode")
+
+// offlineTransport is an [http.RoundTripper] failing every request so that
+// the loaders fall back to the data available in the cache.
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errOffline
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s [flags] [command] [command flags]\n\n", appName)
+	fmt.Fprintf(out, "Without a command, %s starts the interactive terminal UI.\n\n", appName)
+	cli.Usage(out)
+	fmt.Fprintf(out, "\nFlags:\n")
+	flag.PrintDefaults()
+}
+
 func initLogger(scope *gap.Scope) (*slog.Logger, io.Closer, error) {
 	logPath, err := scope.LogPath(logFilename)
 	if err != nil {
@@ -106,63 +284,409 @@ func initLogger(scope *gap.Scope) (*slog.Logger, io.Closer, error) {
 	return logger, logFile, nil
 }
 
-func initCache(scope *gap.Scope) (*bbolt.DB, error) {
+// If allowInMemory is true and the cache database is locked by another process,
+// an in-memory cache is used instead.
+func initCache(
+	scope *gap.Scope,
+	backendName string,
+	allowInMemory bool,
+	logger *slog.Logger,
+) (cache.Backend, io.Closer, error) {
 	cacheDir, err := scope.CacheDir()
 	if err != nil {
-		return nil, fmt.Errorf("could not retrieve the user cache directory: %w", err)
+		return nil, nil, fmt.Errorf("could not retrieve the user cache directory: %w", err)
+	}
+
+	var (
+		backend cache.Backend
+		closer  io.Closer = nopCloser{}
+	)
+	switch backendName {
+	case cacheBackendBolt:
+		backend, closer, err = openBoltBackend(
+			filepath.Join(cacheDir, cacheFile),
+			allowInMemory,
+			logger,
+		)
+	case cacheBackendDir:
+		backend, err = cache.NewDirBackend(filepath.Join(cacheDir, cacheEntriesDir))
+	case cacheBackendMemory:
+		backend = cache.NewMemoryBackend()
+	default:
+		return nil, nil, fmt.Errorf("unknown cache backend %q", backendName)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := ensureCacheSchemas(backend, logger); err != nil {
+		closer.Close()
+
+		if !backend.IsReadOnly() {
+			return nil, nil, err
+		}
+
+		logger.Warn("Outdated read-only cache, using an in-memory cache", slog.Any("error", err))
+		return cache.NewMemoryBackend(), nopCloser{}, nil
+	}
+
+	return backend, closer, nil
+}
+
+// openBoltBackend opens the cache database at path.
+//
+// If the database cannot be opened for writing (e.g. read-only file system) it is
+// opened in read-only mode instead. If it is locked by another process, an error
+// naming that process is returned unless allowInMemory is true, in which case
+// an in-memory cache is used for the session so that several instances can run at once.
+//
+// The database is never copied instead as the process holding the lock may be writing it,
+// the copy could then be inconsistent.
+func openBoltBackend(
+	path string,
+	allowInMemory bool,
+	logger *slog.Logger,
+) (cache.Backend, io.Closer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		logger.Warn("Could not make a new cache directory in filesystem", slog.Any("error", err))
 	}
 
-	if err := os.MkdirAll(cacheDir, os.ModePerm); err != nil {
-		return nil, fmt.Errorf("could not make a new cache directory in filesystem: %w", err)
+	lockHolderPath := filepath.Join(filepath.Dir(path), cacheLockHolderFile)
+
+	cacheDB, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: cacheOpenTimeout})
+	if err == nil {
+		pid := []byte(strconv.Itoa(os.Getpid()))
+		if err := os.WriteFile(lockHolderPath, pid, 0o600); err != nil {
+			logger.Warn("Could not write the cache lock holder", slog.Any("error", err))
+		}
+		return cache.NewBoltBackend(cacheDB), lockedDB{cacheDB, lockHolderPath}, nil
+	}
+
+	if errors.Is(err, bolterrors.ErrTimeout) {
+		lockErr := fmt.Errorf("the cache is locked by %s", describeLockHolder(lockHolderPath))
+		if !allowInMemory {
+			return nil, nil, lockErr
+		}
+
+		fmt.Fprintf(
+			os.Stderr,
+			"Warning: %v, nothing is cached until %s exits\n",
+			lockErr,
+			appName,
+		)
+		logger.Warn("Cache locked by another process, using an in-memory cache")
+		return cache.NewMemoryBackend(), nopCloser{}, nil
+	}
+
+	logger.Warn("Could not open the cache for writing, opening it read-only", slog.Any("error", err))
+	cacheDB, roErr := bbolt.Open(path, 0o600, &bbolt.Options{
+		Timeout:  cacheOpenTimeout,
+		ReadOnly: true,
+	})
+	if roErr != nil {
+		return nil, nil, fmt.Errorf(
+			"could not open the cache database: %w",
+			errors.Join(err, roErr),
+		)
 	}
 
-	cachePath := filepath.Join(cacheDir, cacheFile)
-	cacheDB, err := bbolt.Open(cachePath, 0o600, bbolt.DefaultOptions)
+	return cache.NewBoltBackend(cacheDB), cacheDB, nil
+}
+
+// describeLockHolder returns a description of the process holding the lock
+// of the cache database from the PID it wrote at lockHolderPath.
+func describeLockHolder(lockHolderPath string) string {
+	b, err := os.ReadFile(lockHolderPath)
 	if err != nil {
-		return nil, fmt.Errorf("could not open the cache database: %w", err)
+		return "another process"
+	}
+	return fmt.Sprintf("another %s process (PID %s)", appName, strings.TrimSpace(string(b)))
+}
+
+// lockedDB closes a cache database opened for writing and removes
+// the file naming the process holding its lock.
+type lockedDB struct {
+	db             *bbolt.DB
+	lockHolderPath string
+}
+
+func (l lockedDB) Close() error {
+	os.Remove(l.lockHolderPath)
+	return l.db.Close()
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+// cacheSchemas returns the schema of the values stored in each bucket.
+//
+// The version of the buckets storing LoL Esports data includes the version
+// of the go-lolesports module so that they are wiped whenever it is upgraded.
+func cacheSchemas() map[string]cache.Schema {
+	lolesportsSchemaVersion := "1+go-lolesports@" + moduleVersion(lolesportsModulePath)
+
+	return map[string]cache.Schema{
+		bucketBracketTemplate: {
+			Version: "1",
+			// Entries written before the versioning was introduced have the same format.
+			Migrations: map[string]cache.Migration{"": keepValue},
+		},
+		bucketBracketTemplateResponses: {Version: "1"},
+		bucketAvailableStageIDs:        {Version: "1"},
+		bucketStandings:                {Version: lolesportsSchemaVersion},
+		bucketSplits:                   {Version: lolesportsSchemaVersion},
+		bucketSchedule:                 {Version: lolesportsSchemaVersion},
+		bucketRevealedMatches:          {Version: "1"},
+		bucketRevealedUntil:            {Version: "1"},
 	}
+}
 
-	return cacheDB, nil
+func keepValue(value json.RawMessage) (json.RawMessage, error) { return value, nil }
+
+// moduleVersion returns the version of the dependency identified by path
+// the binary was built with.
+func moduleVersion(path string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == path {
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
+
+// ensureCacheSchemas migrates or wipes the buckets whose entries were written
+// with an outdated schema rather than serving partially decoded data.
+func ensureCacheSchemas(backend cache.Backend, logger *slog.Logger) error {
+	for bucket, schema := range cacheSchemas() {
+		result, err := cache.EnsureSchema(backend, bucket, schema)
+		if err != nil {
+			return err
+		}
+
+		switch result {
+		case cache.SchemaMigrated:
+			logger.Info(
+				"Migrated cache bucket to new schema",
+				slog.String("bucket", bucket),
+				slog.String("version", schema.Version),
+			)
+		case cache.SchemaWiped:
+			logger.Info(
+				"Wiped cache bucket with incompatible schema",
+				slog.String("bucket", bucket),
+				slog.String("version", schema.Version),
+			)
+		}
+	}
+
+	return nil
 }
 
+// caches holds the caches of every bucket of the cache database.
+type caches struct {
+	bracketTemplate          *cache.Cache[rift.BracketTemplate]
+	bracketTemplateResponses *cache.Cache[githubusercontent.CachedResponse]
+	availableStageIDs        *cache.Cache[[]string]
+	standings                *cache.Cache[[]lolesports.Standings]
+	splits                   *cache.Cache[[]lolesports.Split]
+	schedule                 *cache.Cache[lolesports.Schedule]
+	// Matches whose results have been revealed, indexed by match ID.
+	revealedMatches *cache.Cache[bool]
+	// Time up to which the results of the matches have been revealed.
+	revealedUntil *cache.Cache[time.Time]
+
+	// In-memory layers in front of the caches above, used by the loaders.
+	memory struct {
+		bracketTemplate   *cache.Tiered[rift.BracketTemplate]
+		availableStageIDs *cache.Tiered[[]string]
+		standings         *cache.Tiered[[]lolesports.Standings]
+		splits            *cache.Tiered[[]lolesports.Split]
+		schedule          *cache.Tiered[lolesports.Schedule]
+	}
+}
+
+func newCaches(backend cache.Backend, opts ...cache.Option) caches {
+	var c caches
+
+	c.bracketTemplate = cache.New[rift.BracketTemplate](
+		backend,
+		bucketBracketTemplate,
+		cacheDefaultTTL,
+		opts...,
+	)
+	// The files are revalidated on each download, so they never expire.
+	c.bracketTemplateResponses = cache.New[githubusercontent.CachedResponse](
+		backend,
+		bucketBracketTemplateResponses,
+		0,
+		opts...,
+	)
+	c.availableStageIDs = cache.New[[]string](
+		backend,
+		bucketAvailableStageIDs,
+		cacheDefaultTTL,
+		opts...,
+	)
+	c.standings = cache.New[[]lolesports.Standings](
+		backend,
+		bucketStandings,
+		cacheDefaultTTL,
+		opts...,
+	)
+	c.splits = cache.New[[]lolesports.Split](
+		backend,
+		bucketSplits,
+		cacheDefaultTTL,
+		opts...,
+	)
+	c.schedule = cache.New[lolesports.Schedule](
+		backend,
+		bucketSchedule,
+		cacheScheduleTTL,
+		opts...,
+	)
+
+	// The reveals are not data fetched again on demand, they never expire.
+	c.revealedMatches = cache.New[bool](
+		backend,
+		bucketRevealedMatches,
+		0,
+		opts...,
+	)
+	c.revealedUntil = cache.New[time.Time](
+		backend,
+		bucketRevealedUntil,
+		0,
+		opts...,
+	)
+
+	c.memory.bracketTemplate = cache.NewTiered(c.bracketTemplate, cacheMemoryCapacity)
+	c.memory.availableStageIDs = cache.NewTiered(c.availableStageIDs, cacheMemoryCapacity)
+	c.memory.standings = cache.NewTiered(c.standings, cacheMemoryCapacity)
+	c.memory.splits = cache.NewTiered(c.splits, cacheMemoryCapacity)
+	c.memory.schedule = cache.NewTiered(c.schedule, cacheMemoryCapacity)
+
+	return c
+}
+
+// flush writes the pending access times of the entries read during the session.
+func (c caches) flush(logger *slog.Logger) {
+	for _, m := range c.maintainable() {
+		if err := m.Flush(); err != nil {
+			logger.Warn("Failed to flush the cache", slog.Any("error", err))
+		}
+	}
+}
+
+// logCounters logs the number of reads served from memory for each bucket.
+func (c caches) logCounters(logger *slog.Logger) {
+	logger.Info(
+		"Memory cache counters",
+		slog.Any(bucketBracketTemplate, c.memory.bracketTemplate.Counters()),
+		slog.Any(bucketAvailableStageIDs, c.memory.availableStageIDs.Counters()),
+		slog.Any(bucketStandings, c.memory.standings.Counters()),
+		slog.Any(bucketSplits, c.memory.splits.Counters()),
+		slog.Any(bucketSchedule, c.memory.schedule.Counters()),
+	)
+}
+
+// byBucket returns the caches indexed by bucket name to be managed by the cache command.
+func (c caches) byBucket() map[string]cli.Cache {
+	return map[string]cli.Cache{
+		bucketBracketTemplate:          c.bracketTemplate,
+		bucketBracketTemplateResponses: c.bracketTemplateResponses,
+		bucketAvailableStageIDs:        c.availableStageIDs,
+		bucketStandings:                c.standings,
+		bucketSplits:                   c.splits,
+		bucketSchedule:                 c.schedule,
+		bucketRevealedMatches:          c.revealedMatches,
+		bucketRevealedUntil:            c.revealedUntil,
+	}
+}
+
+// maintainable returns the caches to be cleaned by a [cache.Janitor].
+//
+// The matches revealed and the time up to which the results are revealed are left out
+// so that they are never evicted to save space.
+func (c caches) maintainable() []cache.Maintainable {
+	return []cache.Maintainable{
+		c.bracketTemplate,
+		c.bracketTemplateResponses,
+		c.availableStageIDs,
+		c.standings,
+		c.splits,
+		c.schedule,
+	}
+}
+
+// initBracketTemplateLoader returns a loader of the published bracket templates,
+// overridden by the ones of templatesDir if not empty.
 func initBracketTemplateLoader(
 	httpClient *http.Client,
-	cacheDB *bbolt.DB,
+	caches caches,
+	templatesDir string,
 	logger *slog.Logger,
 ) *rift.BracketTemplateLoader {
-	bracketTemplateClient := githubusercontent.NewBracketTemplateClient(httpClient)
+	githubClient := githubusercontent.NewBracketTemplateClient(
+		httpClient,
+		githubusercontent.WithResponseCache(caches.bracketTemplateResponses),
+	)
+	var (
+		bracketTemplateClient rift.BracketTemplateClient = githubClient
 
-	bracketTemplateCache := cache.New[rift.BracketTemplate](
-		cacheDB,
-		bucketBracketTemplate,
-		cacheDefaultTTL,
+		templateCache rift.Cache[rift.BracketTemplate] = caches.memory.bracketTemplate
+		stageIDsCache rift.Cache[[]string]             = caches.memory.availableStageIDs
 	)
 
+	if templatesDir != "" {
+		bracketTemplateClient = rift.NewChainedBracketTemplateClient(
+			templatefs.NewBracketTemplateClient(os.DirFS(templatesDir)),
+			bracketTemplateClient,
+		)
+
+		// Local templates are being authored, they must neither be persisted
+		// nor shadowed by the published ones stored in the cache.
+		memoryBackend := cache.NewMemoryBackend()
+		templateCache = cache.New[rift.BracketTemplate](
+			memoryBackend,
+			bucketBracketTemplate,
+			cacheDefaultTTL,
+		)
+		stageIDsCache = cache.New[[]string](
+			memoryBackend,
+			bucketAvailableStageIDs,
+			cacheDefaultTTL,
+		)
+	}
+
 	return rift.NewBracketTemplateLoader(
 		bracketTemplateClient,
-		bracketTemplateCache,
+		templateCache,
+		stageIDsCache,
 		logger,
+		// Last resort when neither GitHub nor the cache can provide the templates.
+		rift.WithFallbackClient(templatefs.NewBracketTemplateClient(templatefs.Snapshot())),
 	)
 }
 
 func initLoLEsportsLoader(
 	httpClient *http.Client,
-	cacheDB *bbolt.DB,
+	caches caches,
 	logger *slog.Logger,
 ) *rift.LoLEsportsLoader {
 	lolesportsAPIClient := lolesports.NewClient(lolesports.WithHTTPClient(httpClient))
 
-	standingsCache := cache.New[[]lolesports.Standings](
-		cacheDB,
-		bucketStandings,
-		cacheDefaultTTL,
-	)
-
-	splitsCache := cache.New[[]lolesports.Split](
-		cacheDB,
-		bucketSplits,
-		cacheDefaultTTL,
+	return rift.NewLoLEsportsLoader(
+		lolesportsAPIClient,
+		caches.memory.standings,
+		caches.memory.splits,
+		caches.memory.schedule,
+		logger,
 	)
-
-	return rift.NewLoLEsportsLoader(lolesportsAPIClient, standingsCache, splitsCache, logger)
 }